@@ -0,0 +1,63 @@
+package gs
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Shutdowner 接口描述了可以在截止时间内关闭的组件，例如 *http.Server
+// The Shutdowner interface describes a component that can be shut down within a deadline, e.g. *http.Server
+type Shutdowner interface {
+	// Shutdown 关闭组件，ctx 携带了剩余的截止时间
+	// Shutdown shuts down the component, ctx carries the remaining deadline
+	Shutdown(ctx context.Context) error
+}
+
+// RegisterClosers 注册需要关闭的 io.Closer，Close 返回的错误会在关闭完成后汇总
+// 组件的名称优先使用 fmt.Stringer，否则使用类型名称，如果 TerminateSignal 已经关闭，那么返回 ErrAlreadyClosed
+// RegisterClosers registers the io.Closer to be closed, the errors returned by Close are aggregated after the close is completed
+// The name of the component prefers fmt.Stringer, otherwise the type name is used, if the TerminateSignal is already closed, then return ErrAlreadyClosed
+func (s *TerminateSignal) RegisterClosers(closers ...io.Closer) error {
+	// 将非空的 io.Closer 包装成 handle
+	// Wrap the non-nil io.Closer into handles
+	hs := make([]*handle, 0, len(closers))
+	for _, c := range closers {
+		if c != nil {
+			c := c
+			hs = append(hs, newHandle(componentName(c), func(ctx context.Context) error { return c.Close() }))
+		}
+	}
+
+	// 注册所有的 handle
+	// Register all handles
+	return s.register(hs...)
+}
+
+// RegisterShutdowners 注册需要关闭的 Shutdowner，ctx 携带了剩余的截止时间，Shutdown 返回的错误会在关闭完成后汇总
+// 组件的名称优先使用 fmt.Stringer，否则使用类型名称，如果 TerminateSignal 已经关闭，那么返回 ErrAlreadyClosed
+// RegisterShutdowners registers the Shutdowner to be shut down, ctx carries the remaining deadline, the errors returned by Shutdown are aggregated after the close is completed
+// The name of the component prefers fmt.Stringer, otherwise the type name is used, if the TerminateSignal is already closed, then return ErrAlreadyClosed
+func (s *TerminateSignal) RegisterShutdowners(shutdowners ...Shutdowner) error {
+	// 将非空的 Shutdowner 包装成 handle
+	// Wrap the non-nil Shutdowner into handles
+	hs := make([]*handle, 0, len(shutdowners))
+	for _, sd := range shutdowners {
+		if sd != nil {
+			hs = append(hs, newHandle(componentName(sd), sd.Shutdown))
+		}
+	}
+
+	// 注册所有的 handle
+	// Register all handles
+	return s.register(hs...)
+}
+
+// componentName 返回组件在关闭报告中的名称，实现了 fmt.Stringer 时使用 String()，否则使用类型名称
+// componentName returns the name of the component in the shutdown report, String() is used when fmt.Stringer is implemented, otherwise the type name is used
+func componentName(v interface{}) string {
+	if s, ok := v.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", v)
+}
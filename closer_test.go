@@ -0,0 +1,52 @@
+package gs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCloser struct {
+	err error
+}
+
+func (c *testCloser) Close() error {
+	return c.err
+}
+
+type testShutdowner struct {
+	name string
+}
+
+func (s *testShutdowner) String() string {
+	return s.name
+}
+
+func (s *testShutdowner) Shutdown(ctx context.Context) error {
+	return ctx.Err()
+}
+
+func TestRegisterClosers(t *testing.T) {
+	errClose := errors.New("close failed")
+	s := NewTerminateSignal()
+	assert.NoError(t, s.RegisterClosers(&testCloser{}, &testCloser{err: errClose}, nil))
+	assert.NoError(t, s.RegisterShutdowners(&testShutdowner{name: "kafka"}, nil))
+
+	err := s.Close(nil)
+	assert.ErrorIs(t, err, errClose)
+
+	var he *HandleError
+	assert.ErrorAs(t, err, &he)
+	assert.Equal(t, "*gs.testCloser", he.Name)
+
+	names := make([]string, 0)
+	for _, r := range s.Report() {
+		names = append(names, r.Name)
+	}
+	assert.ElementsMatch(t, []string{"*gs.testCloser", "*gs.testCloser", "kafka"}, names)
+
+	assert.ErrorIs(t, s.RegisterClosers(&testCloser{}), ErrAlreadyClosed)
+	assert.ErrorIs(t, s.RegisterShutdowners(&testShutdowner{}), ErrAlreadyClosed)
+}
@@ -0,0 +1,171 @@
+package gs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrAlreadyClosed 表示 TerminateSignal 已经关闭，不能再注册新的处理函数
+// ErrAlreadyClosed indicates that the TerminateSignal is already closed and no more handle functions can be registered
+var ErrAlreadyClosed = errors.New("gs: terminate signal already closed")
+
+// ErrTimeout 表示关闭操作在截止时间之前没有完成
+// ErrTimeout indicates that the shutdown did not complete before the deadline
+var ErrTimeout = errors.New("gs: shutdown timed out")
+
+// TimeoutError 是关闭超时时返回的错误，包含了仍在运行的处理函数名称
+// TimeoutError is the error returned when the shutdown times out, containing the names of the handle functions that were still running
+type TimeoutError struct {
+	// Pending 是超时时已经开始但仍未完成的处理函数名称，尚未开始的处理函数会被跳过
+	// Pending are the names of the handle functions that had started but not completed at the deadline, the handle functions not started yet are skipped
+	Pending []string
+}
+
+// Error 返回错误信息
+// Error returns the error message
+func (e *TimeoutError) Error() string {
+	if len(e.Pending) == 0 {
+		return ErrTimeout.Error()
+	}
+	return ErrTimeout.Error() + ", pending handles: " + strings.Join(e.Pending, ", ")
+}
+
+// Is 使 errors.Is(err, ErrTimeout) 返回 true
+// Is makes errors.Is(err, ErrTimeout) return true
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// ErrHandleTimeout 表示处理函数在自己的超时时间内没有完成
+// ErrHandleTimeout indicates that the handle function did not complete within its own timeout
+var ErrHandleTimeout = errors.New("gs: handle timed out")
+
+// ErrHandlePanic 表示处理函数在执行过程中发生了 panic
+// ErrHandlePanic indicates that the handle function panicked during execution
+var ErrHandlePanic = errors.New("gs: handle panicked")
+
+// PanicError 是处理函数发生 panic 时记录的错误，包含了 panic 的值和调用栈
+// PanicError is the error recorded when a handle function panics, containing the panic value and the stack
+type PanicError struct {
+	// Value 是 panic 的值
+	// Value is the panic value
+	Value interface{}
+
+	// Stack 是发生 panic 时的调用栈
+	// Stack is the stack when the panic occurred
+	Stack []byte
+}
+
+// Error 返回错误信息
+// Error returns the error message
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrHandlePanic.Error(), e.Value)
+}
+
+// Is 使 errors.Is(err, ErrHandlePanic) 返回 true
+// Is makes errors.Is(err, ErrHandlePanic) return true
+func (e *PanicError) Is(target error) bool {
+	return target == ErrHandlePanic
+}
+
+// ErrDependencyCycle 表示处理函数之间的依赖关系存在循环，或者与关闭阶段的顺序冲突
+// ErrDependencyCycle indicates that the dependencies between handle functions contain a cycle, or conflict with the order of the shutdown phases
+var ErrDependencyCycle = errors.New("gs: dependency cycle")
+
+// ErrUnknownDependency 表示处理函数依赖了一个没有注册的名称
+// ErrUnknownDependency indicates that a handle function depends on a name that is not registered
+var ErrUnknownDependency = errors.New("gs: unknown dependency")
+
+// HandleError 是某个处理函数执行失败时记录的错误
+// HandleError is the error recorded when a handle function fails
+type HandleError struct {
+	// Name 是处理函数的名称
+	// Name is the name of the handle function
+	Name string
+
+	// Err 是处理函数失败的原因
+	// Err is the reason why the handle function failed
+	Err error
+}
+
+// Error 返回错误信息
+// Error returns the error message
+func (e *HandleError) Error() string {
+	return e.Name + ": " + e.Err.Error()
+}
+
+// Unwrap 返回处理函数失败的原因
+// Unwrap returns the reason why the handle function failed
+func (e *HandleError) Unwrap() error {
+	return e.Err
+}
+
+// ShutdownError 是关闭过程中所有错误的集合
+// ShutdownError is the collection of all errors during the shutdown
+type ShutdownError struct {
+	// Errors 是关闭过程中发生的所有错误
+	// Errors are all errors that occurred during the shutdown
+	Errors []error
+}
+
+// joinErrors 将多个错误合并为一个 ShutdownError，如果没有错误则返回 nil
+// joinErrors merges multiple errors into a ShutdownError, returns nil if there is no error
+func joinErrors(errs ...error) error {
+	// 过滤掉所有的 nil 错误
+	// Filter out all nil errors
+	list := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			list = append(list, err)
+		}
+	}
+
+	// 如果没有错误，那么返回 nil
+	// If there is no error, then return nil
+	if len(list) == 0 {
+		return nil
+	}
+
+	// 返回错误集合
+	// Return the error collection
+	return &ShutdownError{Errors: list}
+}
+
+// Error 返回错误信息
+// Error returns the error message
+func (e *ShutdownError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return "gs: shutdown failed: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap 返回所有的错误，在 Go 1.20 及以上版本中供 errors.Is 和 errors.As 使用
+// Unwrap returns all errors, used by errors.Is and errors.As in Go 1.20 and above
+func (e *ShutdownError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is 使 errors.Is 能够匹配集合中的任意一个错误
+// Is makes errors.Is match any error in the collection
+func (e *ShutdownError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As 使 errors.As 能够匹配集合中的任意一个错误
+// As makes errors.As match any error in the collection
+func (e *ShutdownError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
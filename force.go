@@ -0,0 +1,84 @@
+package gs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// osExit 是强制退出时调用的函数，测试中可以替换
+// osExit is the function called on forced exit, it can be replaced in tests
+var osExit = os.Exit
+
+// stderr 是强制退出时输出仍在运行的处理函数的位置，测试中可以替换
+// stderr is where the still running handle functions are printed on forced exit, it can be replaced in tests
+var stderr io.Writer = os.Stderr
+
+// pendingHandles 返回所有 TerminateSignal 中尚未执行完成的处理函数名称
+// pendingHandles returns the names of the handle functions that have not completed yet in all TerminateSignal
+func pendingHandles(sigs []*TerminateSignal) []string {
+	pending := make([]string, 0)
+	for _, ts := range sigs {
+		pending = append(pending, ts.pending()...)
+	}
+	return pending
+}
+
+// pendingHandlesAt 返回所有 TerminateSignal 中在 t 时刻尚未执行完成的处理函数名称
+// pendingHandlesAt returns the names of the handle functions that had not completed at time t in all TerminateSignal
+func pendingHandlesAt(sigs []*TerminateSignal, t time.Time) []string {
+	pending := make([]string, 0)
+	for _, ts := range sigs {
+		pending = append(pending, ts.pendingAt(t)...)
+	}
+	return pending
+}
+
+// watchForceExit 在优雅关闭的过程中继续监听系统信号，收到的信号总数达到 cfg.forceExitCount 时，
+// 输出收到的信号和仍在运行的处理函数并以 cfg.forceExitCode 强制退出进程，done 通道关闭后停止监听
+// first 是触发优雅关闭的信号，以编程方式触发或者 context 取消时为 nil，此时不计入信号总数
+// watchForceExit keeps listening to system signals during the graceful shutdown, when the total number of received signals reaches cfg.forceExitCount,
+// it prints the received signals and the still running handle functions and forcibly exits the process with cfg.forceExitCode, it stops listening after the done channel is closed
+// first is the signal that triggered the graceful shutdown, it is nil when triggered programmatically or by the cancellation of the context, in which case it is not counted
+func watchForceExit(cfg *config, quit <-chan os.Signal, first os.Signal, done <-chan struct{}) {
+	// 记录收到的所有信号，触发优雅关闭的信号也计算在内
+	// Record all received signals, including the signal that triggered the graceful shutdown
+	received := make([]string, 0, cfg.forceExitCount)
+	if first != nil {
+		received = append(received, first.String())
+	}
+
+	for {
+		select {
+		// 优雅关闭已经完成，停止监听
+		// The graceful shutdown has completed, stop listening
+		case <-done:
+			return
+
+		// 收到新的信号，记录下来
+		// A new signal is received, record it
+		case sig, ok := <-quit:
+			if !ok {
+				return
+			}
+
+			// 非终止信号不计入强制退出的次数
+			// Non-terminating signals are not counted towards the forced exit
+			if _, ok := cfg.signalHandlers(sig); ok {
+				continue
+			}
+			received = append(received, sig.String())
+
+			// 如果信号总数达到阈值，那么跳过剩余的处理函数并强制退出
+			// If the total number of signals reaches the threshold, then skip the remaining handle functions and forcibly exit
+			if len(received) >= cfg.forceExitCount {
+				fmt.Fprintf(stderr, "gs: received %d signals [%s], forcing exit with code %d, pending handles: [%s]\n",
+					len(received), strings.Join(received, ", "), cfg.forceExitCode, strings.Join(pendingHandles(cfg.sigs), ", "))
+				osExit(cfg.forceExitCode)
+				return
+			}
+		}
+	}
+}
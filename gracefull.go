@@ -1,10 +1,11 @@
 package gs
 
 import (
+	"context"
+	"fmt"
 	"os"
-	"os/signal"
 	"sync"
-	"syscall"
+	"time"
 )
 
 // CloseType 是一个 int8 类型的别名，用于表示关闭类型
@@ -31,29 +32,233 @@ const (
 	ForceSyncClose
 )
 
-// waiting 函数用于等待系统信号，并根据关闭模式和 TerminateSignal 进行不同的处理
-// The waiting function waits for system signals and handles them differently according to the close mode and TerminateSignal
-func waiting(mode CloseType, sigs ...*TerminateSignal) {
+// listen 函数开始监听配置中的系统信号，并返回接收系统信号的通道
+// The listen function starts listening to the system signals in the configuration and returns the channel that receives the system signals
+func listen(cfg *config) chan os.Signal {
 	// 创建一个 os.Signal 类型的通道，用于接收系统信号
 	// Create a channel of type os.Signal to receive system signals
 	quit := make(chan os.Signal, 1)
 
-	// 注册我们关心的系统信号，当这些信号发生时，会发送到 quit 通道
-	// Register the system signals we care about, when these signals occur, they will be sent to the quit channel
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGINT, syscall.SIGQUIT)
+	// 注册我们关心的系统信号以及非终止信号，当这些信号发生时，会发送到 quit 通道
+	// Register the system signals we care about and the non-terminating signals, when these signals occur, they will be sent to the quit channel
+	cfg.source.Notify(quit, cfg.signals...)
+	cfg.setListener(quit)
 
-	// 阻塞等待任何系统信号
-	// Block and wait for any system signal
-	<-quit
+	// 返回接收系统信号的通道
+	// Return the channel that receives the system signals
+	return quit
+}
+
+// waiting 函数用于在 quit 通道上等待系统信号，并根据关闭模式和 TerminateSignal 进行不同的处理，返回关闭报告和关闭过程中的错误
+// The waiting function waits for system signals on the quit channel and handles them differently according to the close mode and TerminateSignal, returns the shutdown report and the error during the shutdown
+func waiting(cfg *config, quit chan os.Signal) (*ShutdownReport, error) {
+	// 阻塞等待任何终止信号、以编程方式触发的关闭或者父 context 被取消，非终止信号执行对应的处理函数后继续等待
+	// Block and wait for any terminating signal, a programmatically triggered shutdown or the cancellation of the parent context, non-terminating signals execute the corresponding handle functions and keep waiting
+	var sig os.Signal
+	var reason string
+	for reason == "" {
+		select {
+		case sig = <-quit:
+			if fns, ok := cfg.signalHandlers(sig); ok {
+				runSignalHandlers(sig, fns)
+				continue
+			}
+			reason = sig.String()
+		case reason = <-cfg.trigger:
+			sig = nil
+			if reason == "" {
+				reason = "shutdown"
+			}
+		case <-cfg.ctx.Done():
+			sig = nil
+			reason = cfg.ctx.Err().Error()
+		}
+	}
+
+	// 通知观察者收到了关闭信号
+	// Notify the observers that the shutdown signal has been received
+	cfg.observers.OnSignalReceived(sig, reason)
+
+	// 在执行任何处理函数之前，将探针切换为关闭中，让就绪探针立即失败
+	// Switch the probes to stopping before any handle function runs, so that the readiness probe fails immediately
+	for _, p := range cfg.probes {
+		p.setState(StateStopping)
+	}
+
+	// 如果启用了强制退出，那么在优雅关闭的过程中继续监听系统信号，否则立即停止接收终止信号，
+	// 这样关闭过程中再收到的终止信号会按照 Go 运行时的默认行为终止进程，而非终止信号在关闭完成之前仍然被订阅并丢弃，
+	// 先订阅新的通道再停止 quit 通道，避免例如 SIGHUP 在两者之间按照默认行为终止进程
+	// If forced exit is enabled, then keep listening to system signals during the graceful shutdown, otherwise stop receiving terminating signals immediately,
+	// so that terminating signals received during the shutdown terminate the process according to the default behavior of the Go runtime, while non-terminating signals stay subscribed and are dropped until the shutdown has finished,
+	// the new channel is subscribed before the quit channel is stopped, to avoid e.g. SIGHUP terminating the process by default in between
+	done := make(chan struct{})
+	var dropped chan os.Signal
+	if cfg.forceExitCount > 1 {
+		go watchForceExit(cfg, quit, sig, done)
+	} else {
+		dropped = make(chan os.Signal, 1)
+		cfg.setListener(dropped)
+		cfg.source.Stop(quit)
+		go dropSignals(dropped, done)
+	}
+
+	// 关闭所有的 TerminateSignal，并根据处理函数的执行情况生成关闭报告
+	// Close all TerminateSignal and generate the shutdown report based on the execution of the handle functions
+	start := time.Now()
+	cfg.observers.OnShutdownStart()
+	err := shutdown(cfg)
+	report := newShutdownReport(sig, reason, start, cfg.sigs)
+
+	// 所有处理函数执行完成，将探针切换为已关闭
+	// All handle functions have completed, switch the probes to stopped
+	for _, p := range cfg.probes {
+		p.setState(StateStopped)
+	}
+
+	// 通知观察者关闭已经完成
+	// Notify the observers that the shutdown has completed
+	cfg.observers.OnShutdownComplete(report, err)
 
-	// 停止接收更多的系统信号
-	// Stop receiving more system signals
-	signal.Stop(quit)
+	// 优雅关闭已经完成，通知强制退出的监听停止
+	// The graceful shutdown has completed, notify the forced exit watcher to stop
+	close(done)
+
+	// 强制退出的监听已经停止，停止接收更多的系统信号，之后注册的非终止信号也不再订阅 quit 通道
+	// The forced exit watcher has stopped, stop receiving more system signals, non-terminating signals registered later no longer subscribe the quit channel
+	cfg.setListener(nil)
+	if dropped != nil {
+		cfg.source.Stop(dropped)
+	}
+	cfg.source.Stop(quit)
 
 	// 关闭 quit 通道
 	// Close the quit channel
 	close(quit)
 
+	// 返回关闭报告和关闭过程中的错误
+	// Return the shutdown report and the error during the shutdown
+	return report, err
+}
+
+// dropSignals 函数在优雅关闭的过程中丢弃收到的非终止信号，done 通道关闭后返回
+// The dropSignals function drops the non-terminating signals received during the graceful shutdown, it returns after the done channel is closed
+func dropSignals(c <-chan os.Signal, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-c:
+		}
+	}
+}
+
+// runSignalHandlers 函数依次执行非终止信号的处理函数，处理函数发生 panic 时输出到 stderr 并继续等待
+// The runSignalHandlers function executes the handle functions of a non-terminating signal in order, when a handle function panics it is printed to stderr and waiting continues
+func runSignalHandlers(sig os.Signal, fns []func()) {
+	for _, fn := range fns {
+		func() {
+			defer func() {
+				if v := recover(); v != nil {
+					fmt.Fprintf(stderr, "gs: handler of signal %v panicked: %v\n", sig, v)
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+// shutdown 函数关闭所有的 TerminateSignal，如果设置了超时时间，超时后最多再等待 cfg.timeoutGrace 并返回 TimeoutError
+// The shutdown function closes all TerminateSignal, if a timeout is set, it waits at most cfg.timeoutGrace more after the timeout and returns a TimeoutError
+func shutdown(cfg *config) error {
+	// 如果没有设置超时时间，那么就一直等待所有的 TerminateSignal 关闭
+	// If no timeout is set, then wait for all TerminateSignal to close
+	if cfg.timeout <= 0 {
+		return drain(context.Background(), cfg, nil)
+	}
+
+	// 创建一个带有截止时间的 context，处理函数可以通过它获取剩余的截止时间
+	// Create a context with the deadline, the handle functions can get the remaining deadline through it
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.timeout)
+	defer cancel()
+
+	// 在新的 goroutine 中关闭所有的 TerminateSignal，完成后关闭 done 通道
+	// Close all TerminateSignal in a new goroutine, and close the done channel when finished
+	// stopErrs 用于在超时时取回关闭前的等待函数已经返回的错误
+	// stopErrs is used to retrieve the errors already returned by the pre-stop wait functions on timeout
+	// late 记录关闭是否在截止时间之后才完成，例如处理函数在截止时间到达后才执行完回退操作
+	// late records whether the shutdown completed only after the deadline, e.g. a handle function finished its fallback after the deadline
+	var err error
+	var late bool
+	done := make(chan struct{})
+	stopErrs := make(chan []error, 1)
+	go func() {
+		err = drain(ctx, cfg, stopErrs)
+		late = ctx.Err() != nil
+		close(done)
+	}()
+
+	// 等待关闭完成或者超时
+	// Wait for the shutdown to complete or time out
+	select {
+	case <-done:
+		if !late {
+			return err
+		}
+	case <-ctx.Done():
+	}
+
+	// 通知观察者关闭超时，超时错误中包含了截止时间到达时仍在运行的处理函数名称
+	// Notify the observers of the timeout, the timeout error contains the names of the handle functions still running when the deadline is reached
+	deadline, _ := ctx.Deadline()
+	pending := pendingHandlesAt(cfg.sigs, deadline)
+	cfg.observers.OnTimeout(pending)
+	errs := []error{&TimeoutError{Pending: pending}}
+
+	// 处理函数通过 ctx 得知超时并执行回退操作（例如 srv.Close），设置了宽限期并且在宽限期内全部完成时合并它们的错误并返回
+	// The handle functions learn about the timeout through ctx and run their fallback (e.g. srv.Close), when a grace period is set and all of them complete within it their errors are merged and returned
+	if cfg.timeoutGrace > 0 {
+		grace := time.NewTimer(cfg.timeoutGrace)
+		defer grace.Stop()
+		select {
+		case <-done:
+			if se, ok := err.(*ShutdownError); ok {
+				errs = append(errs, se.Errors...)
+			}
+			return joinErrors(errs...)
+		case <-grace.C:
+		}
+	}
+
+	// 合并关闭前的等待函数、关闭计划、后台 goroutine 和已经完成的处理函数的错误，这样超时不会掩盖它们
+	// Merge the errors of the pre-stop wait functions, the shutdown plan, the background goroutines and the completed handle functions, so that the timeout does not hide them
+	select {
+	case e := <-stopErrs:
+		errs = append(errs, e...)
+	default:
+	}
+	for _, ts := range cfg.sigs {
+		errs = append(errs, ts.closeErrors()...)
+	}
+
+	// 返回超时错误和其他所有的错误
+	// Return the timeout error and all other errors
+	return joinErrors(errs...)
+}
+
+// drain 函数先执行关闭前的等待，再关闭所有的 TerminateSignal，并合并两者的错误，stopErrs 不为空时会收到关闭前的等待函数的错误
+// The drain function runs the pre-stop wait first, then closes all TerminateSignal, and merges the errors of both, stopErrs receives the errors of the pre-stop wait functions when it is not nil
+func drain(ctx context.Context, cfg *config, stopErrs chan<- []error) error {
+	errs := preStop(ctx, cfg)
+	if stopErrs != nil {
+		stopErrs <- errs
+	}
+	errs = append(errs, closeAll(ctx, cfg.mode, cfg.sigs, cfg.observers)...)
+	return joinErrors(errs...)
+}
+
+// closeAll 函数根据关闭模式关闭所有的 TerminateSignal，并返回所有处理函数的错误
+// The closeAll function closes all TerminateSignal according to the close mode and returns the errors of all handle functions
+func closeAll(ctx context.Context, mode CloseType, sigs []*TerminateSignal, obs Observer) []error {
 	// 如果有提供 TerminateSignal，那么就等待它们全部关闭
 	// If TerminateSignal is provided, then wait for all of them to close
 	if len(sigs) > 0 {
@@ -74,7 +279,7 @@ func waiting(mode CloseType, sigs ...*TerminateSignal) {
 			// 对每一个 TerminateSignal，启动一个 goroutine 进行关闭操作
 			// For each TerminateSignal, start a goroutine to perform the close operation
 			for _, ts := range sigs {
-				go ts.Close(&wg)
+				go ts.close(ctx, ASyncClose, obs, &wg)
 			}
 
 			// 等待所有的 TerminateSignal 都关闭
@@ -87,7 +292,7 @@ func waiting(mode CloseType, sigs ...*TerminateSignal) {
 			// 对每一个 TerminateSignal，同步进行关闭操作
 			// For each TerminateSignal, perform the close operation synchronously
 			for _, ts := range sigs {
-				ts.Close(nil)
+				ts.close(ctx, ASyncClose, obs, nil)
 			}
 
 		// ForceSyncClose 表示强制同步关闭
@@ -96,7 +301,7 @@ func waiting(mode CloseType, sigs ...*TerminateSignal) {
 			// 对每一个 TerminateSignal，强制同步进行关闭操作
 			// For each TerminateSignal, forcibly perform the close operation synchronously
 			for _, ts := range sigs {
-				ts.SyncClose(nil)
+				ts.close(ctx, SyncClose, obs, nil)
 			}
 
 		// 默认行为
@@ -106,28 +311,71 @@ func waiting(mode CloseType, sigs ...*TerminateSignal) {
 			// By default, do nothing
 		}
 	}
+
+	// 收集所有 TerminateSignal 中的错误
+	// Collect the errors in all TerminateSignal
+	errs := make([]error, 0)
+	for _, ts := range sigs {
+		errs = append(errs, ts.errs...)
+	}
+
+	// 返回所有的错误
+	// Return all errors
+	return errs
+}
+
+// WaitFor 函数根据选项等待系统信号并关闭所有的 TerminateSignal，返回关闭报告和关闭过程中的错误
+// The WaitFor function waits for system signals according to the options, closes all TerminateSignal and returns the shutdown report and the error during the shutdown
+func WaitFor(opts ...Option) (*ShutdownReport, error) {
+	// 使用选项创建 Manager，并等待关闭完成
+	// Create a Manager with the options and wait for the shutdown to complete
+	return NewManager(opts...).Wait()
+}
+
+// WaitForAsync 函数等待所有的异步关闭信号，并返回关闭报告和关闭过程中的错误
+// The WaitForAsync function waits for all asynchronous shutdown signals and returns the shutdown report and the error during the shutdown
+func WaitForAsync(sigs ...*TerminateSignal) (*ShutdownReport, error) {
+	// 创建 Manager 并等待关闭完成，传入 ASyncClose 作为关闭模式和 sigs 作为关闭信号
+	// Create a Manager and wait for the shutdown to complete, passing in ASyncClose as the close mode and sigs as the close signals
+	return NewManager(WithCloseMode(ASyncClose), WithTerminateSignals(sigs...)).Wait()
+}
+
+// WaitForSync 函数等待所有的同步关闭信号，并返回关闭报告和关闭过程中的错误
+// The WaitForSync function waits for all synchronous shutdown signals and returns the shutdown report and the error during the shutdown
+func WaitForSync(sigs ...*TerminateSignal) (*ShutdownReport, error) {
+	// 创建 Manager 并等待关闭完成，传入 SyncClose 作为关闭模式和 sigs 作为关闭信号
+	// Create a Manager and wait for the shutdown to complete, passing in SyncClose as the close mode and sigs as the close signals
+	return NewManager(WithCloseMode(SyncClose), WithTerminateSignals(sigs...)).Wait()
+}
+
+// WaitForForceSync 函数等待所有的强制同步关闭信号，并返回关闭报告和关闭过程中的错误
+// The WaitForForceSync function waits for all forced synchronous shutdown signals and returns the shutdown report and the error during the shutdown
+func WaitForForceSync(sigs ...*TerminateSignal) (*ShutdownReport, error) {
+	// 创建 Manager 并等待关闭完成，传入 ForceSyncClose 作为关闭模式和 sigs 作为关闭信号
+	// Create a Manager and wait for the shutdown to complete, passing in ForceSyncClose as the close mode and sigs as the close signals
+	return NewManager(WithCloseMode(ForceSyncClose), WithTerminateSignals(sigs...)).Wait()
 }
 
-// WaitForAsync 函数等待所有的异步关闭信号
-// The WaitForAsync function waits for all asynchronous shutdown signals
-func WaitForAsync(sigs ...*TerminateSignal) {
-	// 调用 waiting 函数，传入 ASyncClose 作为关闭模式和 sigs 作为关闭信号
-	// Call the waiting function, passing in ASyncClose as the close mode and sigs as the close signals
-	waiting(ASyncClose, sigs...)
+// WaitForAsyncContext 函数等待所有的异步关闭信号或者 ctx 被取消，并返回关闭报告和关闭过程中的错误
+// The WaitForAsyncContext function waits for all asynchronous shutdown signals or the cancellation of ctx, and returns the shutdown report and the error during the shutdown
+func WaitForAsyncContext(ctx context.Context, sigs ...*TerminateSignal) (*ShutdownReport, error) {
+	// 创建 Manager 并等待关闭完成，传入 ctx 作为父 context，ASyncClose 作为关闭模式和 sigs 作为关闭信号
+	// Create a Manager and wait for the shutdown to complete, passing in ctx as the parent context, ASyncClose as the close mode and sigs as the close signals
+	return NewManager(WithContext(ctx), WithCloseMode(ASyncClose), WithTerminateSignals(sigs...)).Wait()
 }
 
-// WaitForSync 函数等待所有的同步关闭信号
-// The WaitForSync function waits for all synchronous shutdown signals
-func WaitForSync(sigs ...*TerminateSignal) {
-	// 调用 waiting 函数，传入 SyncClose 作为关闭模式和 sigs 作为关闭信号
-	// Call the waiting function, passing in SyncClose as the close mode and sigs as the close signals
-	waiting(SyncClose, sigs...)
+// WaitForSyncContext 函数等待所有的同步关闭信号或者 ctx 被取消，并返回关闭报告和关闭过程中的错误
+// The WaitForSyncContext function waits for all synchronous shutdown signals or the cancellation of ctx, and returns the shutdown report and the error during the shutdown
+func WaitForSyncContext(ctx context.Context, sigs ...*TerminateSignal) (*ShutdownReport, error) {
+	// 创建 Manager 并等待关闭完成，传入 ctx 作为父 context，SyncClose 作为关闭模式和 sigs 作为关闭信号
+	// Create a Manager and wait for the shutdown to complete, passing in ctx as the parent context, SyncClose as the close mode and sigs as the close signals
+	return NewManager(WithContext(ctx), WithCloseMode(SyncClose), WithTerminateSignals(sigs...)).Wait()
 }
 
-// WaitForForceSync 函数等待所有的强制同步关闭信号
-// The WaitForForceSync function waits for all forced synchronous shutdown signals
-func WaitForForceSync(sigs ...*TerminateSignal) {
-	// 调用 waiting 函数，传入 ForceSyncClose 作为关闭模式和 sigs 作为关闭信号
-	// Call the waiting function, passing in ForceSyncClose as the close mode and sigs as the close signals
-	waiting(ForceSyncClose, sigs...)
+// WaitForForceSyncContext 函数等待所有的强制同步关闭信号或者 ctx 被取消，并返回关闭报告和关闭过程中的错误
+// The WaitForForceSyncContext function waits for all forced synchronous shutdown signals or the cancellation of ctx, and returns the shutdown report and the error during the shutdown
+func WaitForForceSyncContext(ctx context.Context, sigs ...*TerminateSignal) (*ShutdownReport, error) {
+	// 创建 Manager 并等待关闭完成，传入 ctx 作为父 context，ForceSyncClose 作为关闭模式和 sigs 作为关闭信号
+	// Create a Manager and wait for the shutdown to complete, passing in ctx as the parent context, ForceSyncClose as the close mode and sigs as the close signals
+	return NewManager(WithContext(ctx), WithCloseMode(ForceSyncClose), WithTerminateSignals(sigs...)).Wait()
 }
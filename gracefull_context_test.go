@@ -0,0 +1,68 @@
+package gs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForAsyncContext(t *testing.T) {
+	sigs := make([]*TerminateSignal, 0)
+
+	for i := 0; i < 10; i++ {
+		sig := NewTerminateSignal()
+		tts := NewTestTerminateSignal(fmt.Sprintf("test-%d", i))
+		sig.RegisterCancelHandles(tts.Close)
+		sigs = append(sigs, sig)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+
+	r, err := WaitForAsyncContext(ctx, sigs...)
+	assert.NoError(t, err)
+	assert.Nil(t, r.Signal)
+	assert.Equal(t, context.Canceled.Error(), r.Reason)
+	assert.Len(t, r.Handles, 10)
+}
+
+func TestWaitForSyncContext(t *testing.T) {
+	sig := NewTerminateSignal()
+
+	for i := 0; i < 10; i++ {
+		tts := NewTestTerminateSignal(fmt.Sprintf("test-%d", i))
+		sig.RegisterCancelHandles(tts.Close)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	r, err := WaitForSyncContext(ctx, sig)
+	assert.NoError(t, err)
+	assert.Equal(t, context.DeadlineExceeded.Error(), r.Reason)
+	for _, h := range r.Handles {
+		assert.Equal(t, OutcomeOK, h.Outcome)
+	}
+}
+
+func TestWaitForForceSyncContext(t *testing.T) {
+	sig := NewTerminateSignal()
+
+	for i := 0; i < 10; i++ {
+		tts := NewTestTerminateSignal(fmt.Sprintf("test-%d", i))
+		sig.RegisterCancelHandles(tts.Close)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r, err := WaitForForceSyncContext(ctx, sig)
+	assert.NoError(t, err)
+	assert.Len(t, r.Handles, 10)
+}
@@ -3,8 +3,12 @@
 package gs
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"syscall"
 	"testing"
 	"time"
 
@@ -130,3 +134,118 @@ func TestWaitForForceSync_Wait(t *testing.T) {
 
 	WaitForForceSync(sigs...)
 }
+
+func TestWaitFor_DefaultSignals(t *testing.T) {
+	sig := NewTerminateSignal()
+
+	for i := 0; i < 10; i++ {
+		tts := NewTestTerminateSignal(fmt.Sprintf("test-%d", i))
+		sig.RegisterCancelHandles(tts.Close)
+	}
+
+	go func() {
+		time.Sleep(time.Second)
+		p, err := os.FindProcess(os.Getpid())
+		assert.NoError(t, err, "os.FindProcess failed")
+		err = p.Signal(syscall.SIGTERM)
+		assert.NoError(t, err, "os.Signal failed")
+	}()
+
+	r, err := WaitFor(WithTerminateSignals(sig))
+	assert.NoError(t, err)
+	assert.Equal(t, syscall.SIGTERM, r.Signal)
+	assert.Len(t, r.Handles, 10)
+}
+
+func TestWaitFor_WithSignals(t *testing.T) {
+	sig := NewTerminateSignal()
+
+	for i := 0; i < 10; i++ {
+		tts := NewTestTerminateSignal(fmt.Sprintf("test-%d", i))
+		sig.RegisterCancelHandles(tts.Close)
+	}
+
+	go func() {
+		time.Sleep(time.Second)
+		p, err := os.FindProcess(os.Getpid())
+		assert.NoError(t, err, "os.FindProcess failed")
+		err = p.Signal(syscall.SIGUSR1)
+		assert.NoError(t, err, "os.Signal failed")
+	}()
+
+	r, err := WaitFor(WithSignals(syscall.SIGUSR1), WithCloseMode(ForceSyncClose), WithTerminateSignals(sig))
+	assert.NoError(t, err)
+	assert.Equal(t, syscall.SIGUSR1, r.Signal)
+}
+
+func TestWaitFor_WithTimeout(t *testing.T) {
+	sig := NewTerminateSignal()
+	block := make(chan struct{})
+	defer close(block)
+
+	tts := NewTestTerminateSignal("test")
+	sig.RegisterCancelHandles(tts.Close, func() { <-block })
+	sig.RegisterShutdownHandles(func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		return nil
+	})
+
+	go func() {
+		time.Sleep(time.Second)
+		p, err := os.FindProcess(os.Getpid())
+		assert.NoError(t, err, "os.FindProcess failed")
+		err = p.Signal(os.Interrupt)
+		assert.NoError(t, err, "os.Signal failed")
+	}()
+
+	r, err := WaitFor(WithTimeout(100*time.Millisecond), WithTerminateSignals(sig))
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.Equal(t, OutcomePending, r.Handles[1].Outcome)
+
+	var te *TimeoutError
+	assert.True(t, errors.As(err, &te))
+	assert.Len(t, te.Pending, 1)
+	assert.Contains(t, te.Pending[0], "TestWaitFor_WithTimeout")
+}
+
+func TestWaitFor_WithForceExit(t *testing.T) {
+	codes := make(chan int, 1)
+	buf := bytes.Buffer{}
+	osExit = func(code int) { codes <- code }
+	stderr = &buf
+	defer func() {
+		osExit = os.Exit
+		stderr = os.Stderr
+	}()
+
+	sig := NewTerminateSignal()
+	block := make(chan struct{})
+	sig.Register("kafka", func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+
+	go func() {
+		time.Sleep(time.Second)
+		p, err := os.FindProcess(os.Getpid())
+		assert.NoError(t, err, "os.FindProcess failed")
+		err = p.Signal(os.Interrupt)
+		assert.NoError(t, err, "os.Signal failed")
+		time.Sleep(100 * time.Millisecond)
+		err = p.Signal(os.Interrupt)
+		assert.NoError(t, err, "os.Signal failed")
+
+		select {
+		case code := <-codes:
+			assert.Equal(t, 3, code)
+		case <-time.After(time.Second):
+			assert.Fail(t, "forced exit was not triggered")
+		}
+		close(block)
+	}()
+
+	_, err := WaitFor(WithForceExit(2, 3), WithTerminateSignals(sig))
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "pending handles: [kafka]")
+}
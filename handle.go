@@ -0,0 +1,238 @@
+package gs
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// handle 结构体包含了一个需要在终止信号发生时执行的处理函数及其运行状态
+// The handle struct contains a handle function to be executed when the termination signal occurs and its running state
+type handle struct {
+	// name 是处理函数的名称，用于在关闭报告中标识处理函数
+	// name is the name of the handle function, used to identify the handle function in the shutdown report
+	name string
+
+	// fn 是需要执行的处理函数，ctx 携带了剩余的截止时间
+	// fn is the handle function to be executed, ctx carries the remaining deadline
+	fn func(ctx context.Context) error
+
+	// phase 是处理函数所在的关闭阶段，阶段按从小到大的顺序依次执行
+	// phase is the shutdown phase of the handle function, phases are executed in ascending order
+	phase int
+
+	// before 是必须在该处理函数之后关闭的处理函数名称
+	// before are the names of the handle functions that must close after this handle function
+	before []string
+
+	// after 是必须在该处理函数之前关闭的处理函数名称
+	// after are the names of the handle functions that must close before this handle function
+	after []string
+
+	// timeout 是处理函数自己的超时时间，小于等于 0 表示没有超时时间
+	// timeout is the handle function's own timeout, less than or equal to 0 means no timeout
+	timeout time.Duration
+
+	// mu 是一个 sync.Mutex 实例，用于保护处理函数的运行状态
+	// mu is a sync.Mutex instance, used to protect the running state of the handle function
+	mu sync.Mutex
+
+	// start 是处理函数开始执行的时间
+	// start is the time when the handle function started
+	start time.Time
+
+	// end 是处理函数执行完成的时间
+	// end is the time when the handle function completed
+	end time.Time
+
+	// outcome 是处理函数的执行结果
+	// outcome is the result of the handle function
+	outcome Outcome
+
+	// err 是处理函数执行失败时记录的错误
+	// err is the error recorded when the handle function fails
+	err error
+}
+
+// HandleOption 是一个用于修改处理函数注册参数的函数类型
+// HandleOption is a function type used to modify the registration parameters of a handle function
+type HandleOption func(*handle)
+
+// WithPhase 设置处理函数所在的关闭阶段，同一阶段的处理函数并发执行，不同阶段按从小到大的顺序依次执行，默认为 0
+// WithPhase sets the shutdown phase of the handle function, handle functions in the same phase run concurrently, phases run in ascending order, the default is 0
+func WithPhase(phase int) HandleOption {
+	return func(h *handle) {
+		h.phase = phase
+	}
+}
+
+// Before 声明该处理函数必须在指定名称的处理函数之前关闭，例如 Register("http", fn, Before("db"))
+// Before declares that the handle function must close before the handle functions with the given names, e.g. Register("http", fn, Before("db"))
+func Before(names ...string) HandleOption {
+	return func(h *handle) {
+		h.before = append(h.before, names...)
+	}
+}
+
+// After 声明该处理函数必须在指定名称的处理函数之后关闭，例如 Register("db", fn, After("http"))
+// After declares that the handle function must close after the handle functions with the given names, e.g. Register("db", fn, After("http"))
+func After(names ...string) HandleOption {
+	return func(h *handle) {
+		h.after = append(h.after, names...)
+	}
+}
+
+// WithName 设置处理函数的名称，覆盖注册时给出的名称或者适配器的默认名称，例如 RegisterHTTPServer(sig, srv, WithName("api"))
+// WithName sets the name of the handle function, overriding the name given at registration or the default name of an adapter, e.g. RegisterHTTPServer(sig, srv, WithName("api"))
+func WithName(name string) HandleOption {
+	return func(h *handle) {
+		if name != "" {
+			h.name = name
+		}
+	}
+}
+
+// WithHandleTimeout 设置处理函数自己的超时时间，超时后不再等待该处理函数并将其记录为超时
+// WithHandleTimeout sets the handle function's own timeout, after the timeout the handle function is no longer waited for and is recorded as timed out
+func WithHandleTimeout(timeout time.Duration) HandleOption {
+	return func(h *handle) {
+		h.timeout = timeout
+	}
+}
+
+// newHandle 创建一个新的 handle 实例
+// newHandle creates a new handle instance
+func newHandle(name string, fn func(ctx context.Context) error, opts ...HandleOption) *handle {
+	// 初始化 handle，并依次应用所有的选项
+	// Initialize the handle and apply all options in order
+	h := &handle{name: name, fn: fn}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(h)
+		}
+	}
+
+	// 返回 handle
+	// Return the handle
+	return h
+}
+
+// begin 记录处理函数开始执行的时间
+// begin records the time when the handle function started
+func (h *handle) begin() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.start = time.Now()
+}
+
+// finish 记录处理函数的执行结果，根据错误类型推导出执行结果
+// finish records the result of the handle function, the result is derived from the error type
+func (h *handle) finish(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// 记录完成时间，如果处理函数没有开始执行，那么开始时间与完成时间相同
+	// Record the completion time, if the handle function did not start, then the start time is the same as the completion time
+	h.end = time.Now()
+	if h.start.IsZero() {
+		h.start = h.end
+	}
+
+	// 根据错误类型推导出执行结果
+	// Derive the result from the error type
+	switch {
+	case err == nil:
+		h.outcome = OutcomeOK
+	case errors.Is(err, ErrHandlePanic):
+		h.outcome = OutcomePanic
+	case errors.Is(err, ErrHandleTimeout):
+		h.outcome = OutcomeTimeout
+	default:
+		h.outcome = OutcomeError
+	}
+
+	// 记录带有处理函数名称的错误
+	// Record the error with the name of the handle function
+	if err != nil {
+		h.err = &HandleError{Name: h.name, Err: err}
+	}
+}
+
+// skip 记录处理函数因为 context 已经超时而没有执行
+// skip records that the handle function was not executed because the context had already timed out
+func (h *handle) skip() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.start = time.Now()
+	h.end = h.start
+	h.outcome = OutcomeSkipped
+}
+
+// report 返回处理函数当前的执行报告
+// report returns the current execution report of the handle function
+func (h *handle) report() HandleReport {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// 计算执行时长，未完成的处理函数的执行时长为 0
+	// Calculate the execution duration, the duration of an unfinished handle function is 0
+	var d time.Duration
+	if !h.end.IsZero() {
+		d = h.end.Sub(h.start)
+	}
+
+	// 返回执行报告
+	// Return the execution report
+	return HandleReport{
+		Name:     h.name,
+		Start:    h.start,
+		End:      h.end,
+		Duration: d,
+		Outcome:  h.outcome,
+		Err:      h.err,
+	}
+}
+
+// run 执行处理函数，如果处理函数发生 panic，那么恢复并将其转换为 PanicError
+// run executes the handle function, if the handle function panics, then recover and convert it into a PanicError
+func (h *handle) run(ctx context.Context) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return h.fn(ctx)
+}
+
+// wrapHandle 将一个没有参数和返回值的处理函数包装成带有 context 和错误返回值的处理函数
+// wrapHandle wraps a handle function without parameters and return values into a handle function with context and error return value
+func wrapHandle(fn func()) func(ctx context.Context) error {
+	return func(context.Context) error {
+		fn()
+		return nil
+	}
+}
+
+// funcName 返回函数的短名称，例如 "gs.(*TestTerminateSignal).Close"
+// funcName returns the short name of the function, e.g. "gs.(*TestTerminateSignal).Close"
+func funcName(fn interface{}) string {
+	// 通过函数指针获取运行时函数信息
+	// Get the runtime function information through the function pointer
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "unknown"
+	}
+
+	// 去掉包路径前缀和方法值的 "-fm" 后缀
+	// Remove the package path prefix and the "-fm" suffix of method values
+	name := f.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.TrimSuffix(name, "-fm")
+}
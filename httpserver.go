@@ -0,0 +1,46 @@
+package gs
+
+import (
+	"context"
+	"net/http"
+)
+
+// httpServerName 是 RegisterHTTPServer 注册的处理函数的默认名称
+// httpServerName is the default name of the handle function registered by RegisterHTTPServer
+const httpServerName = "http-server"
+
+// RegisterHTTPServer 将 http.Server 注册到 TerminateSignal，关闭时使用剩余的截止时间调用 srv.Shutdown，
+// 截止时间到达后回退到 srv.Close 强制关闭所有连接，错误会记录在关闭报告和汇总错误中，使用 WithTimeoutGrace 等待 WithTimeout 截止时间之后的回退操作
+// 处理函数的默认名称为 "http-server"，可以使用 WithName 修改，opts 同样可以设置关闭阶段、依赖和超时时间
+// RegisterHTTPServer registers the http.Server to the TerminateSignal, on close srv.Shutdown is called with the remaining deadline,
+// after the deadline it falls back to srv.Close to forcibly close all connections, the error is recorded in the shutdown report and the aggregated error, use WithTimeoutGrace to wait for the fallback after the deadline of WithTimeout
+// The default name of the handle function is "http-server", which can be changed with WithName, opts can also set the shutdown phase, dependencies and timeout
+func RegisterHTTPServer(sig *TerminateSignal, srv *http.Server, opts ...HandleOption) (unregister func() bool, err error) {
+	// 如果没有提供 http.Server，那么直接返回
+	// If no http.Server is provided, then return directly
+	if sig == nil || srv == nil {
+		return func() bool { return false }, nil
+	}
+
+	// 以默认名称注册，opts 中的 WithName 会覆盖默认名称
+	// Register with the default name, WithName in opts overrides the default name
+	return sig.Register(httpServerName, shutdownHTTPServer(srv), opts...)
+}
+
+// shutdownHTTPServer 返回关闭 http.Server 的处理函数，srv.Shutdown 失败时（例如截止时间到达）调用 srv.Close
+// shutdownHTTPServer returns the handle function that shuts down the http.Server, srv.Close is called when srv.Shutdown fails (e.g. the deadline is reached)
+func shutdownHTTPServer(srv *http.Server) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		// 优雅关闭：停止接收新的连接，等待在途请求完成
+		// Graceful shutdown: stop accepting new connections and wait for the in-flight requests to complete
+		err := srv.Shutdown(ctx)
+		if err == nil {
+			return nil
+		}
+
+		// 优雅关闭失败，强制关闭所有连接，返回优雅关闭的错误
+		// The graceful shutdown failed, forcibly close all connections and return the error of the graceful shutdown
+		_ = srv.Close()
+		return err
+	}
+}
@@ -0,0 +1,114 @@
+package gs
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func startHTTPServer(t *testing.T, handler http.HandlerFunc) (*http.Server, string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	srv := &http.Server{Handler: handler}
+	go func() { _ = srv.Serve(ln) }()
+	return srv, "http://" + ln.Addr().String()
+}
+
+func TestRegisterHTTPServer(t *testing.T) {
+	srv, url := startHTTPServer(t, func(w http.ResponseWriter, r *http.Request) {})
+	resp, err := http.Get(url)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	sig := NewTerminateSignal()
+	_, err = RegisterHTTPServer(sig, srv, WithName("api"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, sig.Close(nil))
+	assert.Equal(t, "api", sig.Report()[0].Name)
+	assert.Equal(t, OutcomeOK, sig.Report()[0].Outcome)
+
+	_, err = http.Get(url)
+	assert.Error(t, err)
+}
+
+func TestRegisterHTTPServer_Deadline(t *testing.T) {
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	defer close(release)
+	srv, url := startHTTPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-release
+	})
+
+	reqErr := make(chan error, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		reqErr <- err
+	}()
+	<-entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := shutdownHTTPServer(srv)(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	select {
+	case err := <-reqErr:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("in-flight request was not closed")
+	}
+}
+
+func TestRegisterHTTPServer_Timeout(t *testing.T) {
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	defer close(release)
+	srv, url := startHTTPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-release
+	})
+
+	reqErr := make(chan error, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		reqErr <- err
+	}()
+	<-entered
+
+	sig := NewTerminateSignal()
+	_, err := RegisterHTTPServer(sig, srv)
+	assert.NoError(t, err)
+
+	m := NewManager(WithSignalSource(NewManualSignalSource()), WithTerminateSignals(sig), WithTimeout(200*time.Millisecond), WithTimeoutGrace(time.Second))
+	m.Shutdown("test")
+	r, err := m.Wait()
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, ErrTimeout)
+	var te *TimeoutError
+	assert.ErrorAs(t, err, &te)
+	assert.Equal(t, []string{httpServerName}, te.Pending)
+	assert.Equal(t, httpServerName, r.Handles[0].Name)
+	assert.Equal(t, OutcomeError, r.Handles[0].Outcome)
+	assert.ErrorIs(t, r.Handles[0].Err, context.DeadlineExceeded)
+
+	select {
+	case err := <-reqErr:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("in-flight request was not closed")
+	}
+}
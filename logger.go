@@ -0,0 +1,120 @@
+//go:build go1.21
+
+package gs
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// 日志记录中使用的属性键，所有的关闭事件都使用相同的键
+// The attribute keys used in the log records, all shutdown events use the same keys
+const (
+	// LogKeySignal 是收到的信号
+	// LogKeySignal is the received signal
+	LogKeySignal = "signal"
+
+	// LogKeyReason 是关闭原因
+	// LogKeyReason is the shutdown reason
+	LogKeyReason = "reason"
+
+	// LogKeyHandle 是处理函数的名称
+	// LogKeyHandle is the name of the handle function
+	LogKeyHandle = "handle"
+
+	// LogKeyDuration 是处理函数或者整个关闭过程的耗时
+	// LogKeyDuration is the duration of the handle function or the whole shutdown
+	LogKeyDuration = "duration"
+
+	// LogKeyError 是处理函数或者整个关闭过程的错误
+	// LogKeyError is the error of the handle function or the whole shutdown
+	LogKeyError = "error"
+
+	// LogKeyPending 是仍在运行的处理函数名称
+	// LogKeyPending are the names of the handle functions still running
+	LogKeyPending = "pending"
+
+	// LogKeyHandles 是已经执行的处理函数数量
+	// LogKeyHandles is the number of executed handle functions
+	LogKeyHandles = "handles"
+)
+
+// WithLogger 使用 slog.Logger 输出关闭过程的结构化日志：收到信号、每个处理函数的开始和结束（耗时和错误）以及最终的汇总
+// WithLogger outputs structured logs of the shutdown with slog.Logger: signal receipt, start and end of every handle function (duration and error) and the final summary
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *config) {
+		if logger != nil {
+			c.observers = append(c.observers, &slogObserver{logger: logger})
+		}
+	}
+}
+
+// slogObserver 是使用 slog.Logger 输出日志的 Observer
+// slogObserver is an Observer that outputs logs with slog.Logger
+type slogObserver struct {
+	logger *slog.Logger
+}
+
+// log 使用给定的级别、消息和属性输出一条日志
+// log outputs a log record with the given level, message and attributes
+func (o *slogObserver) log(level slog.Level, msg string, attrs ...slog.Attr) {
+	o.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+// OnSignalReceived 在收到关闭信号时输出日志
+// OnSignalReceived outputs a log record when the shutdown signal is received
+func (o *slogObserver) OnSignalReceived(sig os.Signal, reason string) {
+	attrs := []slog.Attr{slog.String(LogKeyReason, reason)}
+	if sig != nil {
+		attrs = append(attrs, slog.String(LogKeySignal, sig.String()))
+	}
+	o.log(slog.LevelInfo, "gs: shutdown signal received", attrs...)
+}
+
+// OnShutdownStart 在关闭开始时输出日志
+// OnShutdownStart outputs a log record when the shutdown starts
+func (o *slogObserver) OnShutdownStart() {
+	o.log(slog.LevelInfo, "gs: shutdown started")
+}
+
+// OnHandleStart 在处理函数开始执行时输出日志
+// OnHandleStart outputs a log record when a handle function starts
+func (o *slogObserver) OnHandleStart(name string) {
+	o.log(slog.LevelInfo, "gs: handle started", slog.String(LogKeyHandle, name))
+}
+
+// OnHandleDone 在处理函数执行完成时输出日志
+// OnHandleDone outputs a log record when a handle function completes
+func (o *slogObserver) OnHandleDone(name string, duration time.Duration, err error) {
+	if err != nil {
+		o.log(slog.LevelError, "gs: handle failed", slog.String(LogKeyHandle, name), slog.Duration(LogKeyDuration, duration), slog.Any(LogKeyError, err))
+		return
+	}
+	o.log(slog.LevelInfo, "gs: handle done", slog.String(LogKeyHandle, name), slog.Duration(LogKeyDuration, duration))
+}
+
+// OnShutdownComplete 在关闭完成时输出日志
+// OnShutdownComplete outputs a log record when the shutdown completes
+func (o *slogObserver) OnShutdownComplete(report *ShutdownReport, err error) {
+	attrs := []slog.Attr{
+		slog.String(LogKeyReason, report.Reason),
+		slog.Duration(LogKeyDuration, report.Duration),
+		slog.Int(LogKeyHandles, len(report.Handles)),
+	}
+	if report.Signal != nil {
+		attrs = append(attrs, slog.String(LogKeySignal, report.Signal.String()))
+	}
+	if err != nil {
+		o.log(slog.LevelError, "gs: shutdown completed with errors", append(attrs, slog.Any(LogKeyError, err))...)
+		return
+	}
+	o.log(slog.LevelInfo, "gs: shutdown completed", attrs...)
+}
+
+// OnTimeout 在关闭超时时输出日志
+// OnTimeout outputs a log record when the shutdown times out
+func (o *slogObserver) OnTimeout(pending []string) {
+	o.log(slog.LevelWarn, "gs: shutdown timed out", slog.Any(LogKeyPending, pending))
+}
@@ -0,0 +1,60 @@
+//go:build go1.21
+
+package gs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithLogger(t *testing.T) {
+	buf := bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	src := NewManualSignalSource()
+	sig := NewTerminateSignal()
+	sig.Register("http", func(ctx context.Context) error { return nil })
+	sig.Register("db", func(ctx context.Context) error { return errors.New("db failed") }, After("http"))
+
+	m := NewManager(WithSignalSource(src), WithTerminateSignals(sig), WithLogger(logger))
+	m.Start()
+	assert.True(t, src.Fire(syscall.SIGTERM))
+	_, err := m.Wait()
+	assert.Error(t, err)
+
+	records := make([]map[string]interface{}, 0)
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		r := map[string]interface{}{}
+		assert.NoError(t, dec.Decode(&r))
+		records = append(records, r)
+	}
+
+	msgs := make([]string, 0, len(records))
+	for _, r := range records {
+		msgs = append(msgs, r["msg"].(string))
+	}
+	assert.Equal(t, []string{
+		"gs: shutdown signal received",
+		"gs: shutdown started",
+		"gs: handle started",
+		"gs: handle done",
+		"gs: handle started",
+		"gs: handle failed",
+		"gs: shutdown completed with errors",
+	}, msgs)
+
+	assert.Equal(t, "terminated", records[0][LogKeySignal])
+	assert.Equal(t, "http", records[3][LogKeyHandle])
+	assert.Contains(t, records[3], LogKeyDuration)
+	assert.Equal(t, "db", records[5][LogKeyHandle])
+	assert.Contains(t, records[5][LogKeyError], "db failed")
+	assert.Equal(t, float64(2), records[6][LogKeyHandles])
+}
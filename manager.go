@@ -0,0 +1,144 @@
+package gs
+
+import (
+	"os"
+	"sync"
+	"syscall"
+)
+
+// Manager 结构体管理一次优雅关闭，除了系统信号之外，还可以通过 Shutdown 或 Trigger 以编程方式触发关闭
+// Start 在后台开始监听，调用者可以像对待 http.Server.ListenAndServe 的错误一样在 select 中等待 Done 通道
+// The Manager struct manages a graceful shutdown, besides system signals, the shutdown can also be triggered programmatically through Shutdown or Trigger
+// Start listens in the background, the caller can wait on the Done channel in a select, just like the errors of http.Server.ListenAndServe
+type Manager struct {
+	// cfg 是等待函数的配置
+	// cfg is the configuration of the waiting function
+	cfg *config
+
+	// trigger 是用于以编程方式触发关闭的通道，携带了关闭的原因
+	// trigger is the channel used to trigger the shutdown programmatically, carrying the reason of the shutdown
+	trigger chan string
+
+	// once 是一个 sync.Once 实例，用于确保关闭只被触发一次
+	// once is a sync.Once instance, used to ensure that the shutdown is only triggered once
+	once sync.Once
+
+	// startOnce 是一个 sync.Once 实例，用于确保后台监听只启动一次
+	// startOnce is a sync.Once instance, used to ensure that the background listening is only started once
+	startOnce sync.Once
+
+	// done 是关闭完成后关闭的通道
+	// done is the channel closed after the shutdown is completed
+	done chan struct{}
+
+	// report 是关闭完成后的关闭报告
+	// report is the shutdown report after the shutdown is completed
+	report *ShutdownReport
+
+	// err 是关闭过程中的错误
+	// err is the error during the shutdown
+	err error
+}
+
+// NewManager 根据选项创建一个新的 Manager 实例
+// NewManager creates a new Manager instance according to the options
+func NewManager(opts ...Option) *Manager {
+	// 初始化 Manager 结构体
+	// Initialize the Manager struct
+	m := &Manager{
+		cfg:       newConfig(opts...),
+		trigger:   make(chan string, 1),
+		once:      sync.Once{},
+		startOnce: sync.Once{},
+		done:      make(chan struct{}),
+	}
+
+	// 将触发通道交给等待函数
+	// Hand the trigger channel over to the waiting function
+	m.cfg.trigger = m.trigger
+
+	// 返回 Manager 实例的指针
+	// Return the pointer to the Manager instance
+	return m
+}
+
+// Shutdown 以编程方式触发关闭，reason 是关闭的原因，会记录在关闭报告中，只有第一次调用有效
+// Shutdown triggers the shutdown programmatically, reason is the reason of the shutdown and is recorded in the shutdown report, only the first call takes effect
+func (m *Manager) Shutdown(reason string) {
+	m.once.Do(func() {
+		m.trigger <- reason
+	})
+}
+
+// Trigger 以编程方式触发关闭，关闭的原因为 "trigger"
+// Trigger triggers the shutdown programmatically, the reason of the shutdown is "trigger"
+func (m *Manager) Trigger() {
+	m.Shutdown("trigger")
+}
+
+// OnSignal 注册一个非终止信号的处理函数，收到 sig 时执行 fn，等待不会结束，可以在 Start 之后调用，新的信号会立即开始监听
+// 如果 sig 同时在需要监听的系统信号中，那么它不再触发关闭
+// OnSignal registers a handle function for a non-terminating signal, fn is executed when sig is received and the waiting does not end, it can be called after Start, the new signal is listened to immediately
+// If sig is also in the system signals to listen to, then it no longer triggers the shutdown
+func (m *Manager) OnSignal(sig os.Signal, fn func()) {
+	WithSignalHandler(sig, fn)(m.cfg)
+}
+
+// OnReload 注册一个在收到 SIGHUP 时执行的重新加载处理函数，可以在 Start 之后调用
+// OnReload registers a reload handle function executed when SIGHUP is received, it can be called after Start
+func (m *Manager) OnReload(fn func()) {
+	m.OnSignal(syscall.SIGHUP, fn)
+}
+
+// Start 开始监听系统信号并立即返回，收到信号或者以编程方式触发关闭后，在后台关闭所有的 TerminateSignal，多次调用只有第一次有效
+// Start starts listening to system signals and returns immediately, after a signal is received or the shutdown is triggered programmatically, all TerminateSignal are closed in the background, only the first call takes effect
+func (m *Manager) Start() {
+	m.startOnce.Do(func() {
+		// 在返回之前注册系统信号，确保 Start 返回后发送的信号不会丢失
+		// Register the system signals before returning, to ensure that signals sent after Start returns are not lost
+		quit := listen(m.cfg)
+
+		// 在后台等待并关闭，完成后关闭 done 通道
+		// Wait and close in the background, and close the done channel when finished
+		go func() {
+			m.report, m.err = waiting(m.cfg, quit)
+			close(m.done)
+		}()
+	})
+}
+
+// Done 返回一个通道，关闭完成后该通道会被关闭，在调用 Start 或 Wait 之前该通道永远不会被关闭
+// Done returns a channel that is closed after the shutdown is completed, the channel is never closed before Start or Wait is called
+func (m *Manager) Done() <-chan struct{} {
+	return m.done
+}
+
+// Err 返回关闭过程中的错误，关闭完成之前返回 nil
+// Err returns the error during the shutdown, returns nil before the shutdown is completed
+func (m *Manager) Err() error {
+	select {
+	case <-m.done:
+		return m.err
+	default:
+		return nil
+	}
+}
+
+// Report 返回关闭报告，关闭完成之前返回 nil
+// Report returns the shutdown report, returns nil before the shutdown is completed
+func (m *Manager) Report() *ShutdownReport {
+	select {
+	case <-m.done:
+		return m.report
+	default:
+		return nil
+	}
+}
+
+// Wait 启动监听（如果还没有启动）并阻塞等待关闭完成，返回关闭报告和关闭过程中的错误
+// Wait starts listening (if not started yet) and blocks until the shutdown is completed, returns the shutdown report and the error during the shutdown
+func (m *Manager) Wait() (*ShutdownReport, error) {
+	m.Start()
+	<-m.done
+	return m.report, m.err
+}
@@ -0,0 +1,277 @@
+package gs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_Shutdown(t *testing.T) {
+	sig := NewTerminateSignal()
+	for i := 0; i < 10; i++ {
+		tts := NewTestTerminateSignal(fmt.Sprintf("test-%d", i))
+		sig.RegisterCancelHandles(tts.Close)
+	}
+
+	m := NewManager(WithTerminateSignals(sig))
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		m.Shutdown("leader lease lost")
+		m.Shutdown("ignored")
+	}()
+
+	r, err := m.Wait()
+	assert.NoError(t, err)
+	assert.Nil(t, r.Signal)
+	assert.Equal(t, "leader lease lost", r.Reason)
+	assert.Len(t, r.Handles, 10)
+	assert.Error(t, sig.GetStopContext().Err())
+}
+
+func TestManager_Trigger(t *testing.T) {
+	sig := NewTerminateSignal()
+	errFlush := errors.New("flush failed")
+	sig.Register("kafka", func(ctx context.Context) error { return errFlush })
+
+	m := NewManager(WithCloseMode(ForceSyncClose), WithTerminateSignals(sig))
+	m.Trigger()
+
+	r, err := m.Wait()
+	assert.True(t, errors.Is(err, errFlush))
+	assert.Equal(t, "trigger", r.Reason)
+	assert.Equal(t, OutcomeError, r.Handles[0].Outcome)
+}
+
+func TestManager_Start(t *testing.T) {
+	sig := NewTerminateSignal()
+	sig.Register("db", func(ctx context.Context) error { return errors.New("close failed") })
+
+	m := NewManager(WithTerminateSignals(sig))
+	m.Start()
+	m.Start()
+	assert.NoError(t, m.Err())
+	assert.Nil(t, m.Report())
+
+	serverErr := make(chan error)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		m.Shutdown("server stopped")
+	}()
+
+	select {
+	case <-serverErr:
+		assert.Fail(t, "unexpected server error")
+	case <-m.Done():
+	}
+
+	assert.Error(t, m.Err())
+	assert.Equal(t, "server stopped", m.Report().Reason)
+
+	r, err := m.Wait()
+	assert.Equal(t, m.Report(), r)
+	assert.Equal(t, m.Err(), err)
+}
+
+func TestManager_OnSignal(t *testing.T) {
+	src := NewManualSignalSource()
+	sig := NewTerminateSignal()
+	tts := NewTestTerminateSignal("test")
+	sig.RegisterCancelHandles(tts.Close)
+
+	reloads := make(chan struct{}, 2)
+	reopens := make(chan struct{}, 1)
+	m := NewManager(WithSignalSource(src), WithTerminateSignals(sig))
+	m.OnReload(func() { reloads <- struct{}{} })
+	m.OnReload(func() { panic("reload failed") })
+	m.OnSignal(syscall.SIGQUIT, func() { reopens <- struct{}{} })
+	m.Start()
+
+	assert.True(t, src.Fire(syscall.SIGHUP))
+	<-reloads
+	assert.True(t, src.Fire(syscall.SIGQUIT))
+	<-reopens
+	assert.True(t, src.Fire(syscall.SIGHUP))
+	<-reloads
+
+	select {
+	case <-m.Done():
+		assert.Fail(t, "non-terminating signal ended the wait loop")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.True(t, src.Fire(syscall.SIGTERM))
+	r, err := m.Wait()
+	assert.NoError(t, err)
+	assert.Equal(t, syscall.SIGTERM, r.Signal)
+}
+
+func TestManager_OnSignalAfterStart(t *testing.T) {
+	src := NewManualSignalSource()
+	m := NewManager(WithSignalSource(src))
+	m.Start()
+	assert.False(t, src.Fire(syscall.SIGHUP))
+
+	reloads := make(chan struct{}, 1)
+	m.OnReload(func() { reloads <- struct{}{} })
+	assert.True(t, src.Fire(syscall.SIGHUP))
+	<-reloads
+
+	m.Shutdown("test")
+	r, err := m.Wait()
+	assert.NoError(t, err)
+	assert.Nil(t, r.Signal)
+
+	m.OnReload(func() {})
+	assert.False(t, src.Fire(syscall.SIGHUP))
+}
+
+func TestManager_TimeoutKeepsErrors(t *testing.T) {
+	sig := NewTerminateSignal()
+	errFlush := errors.New("flush failed")
+	errWait := errors.New("wait failed")
+	release := make(chan struct{})
+	defer close(release)
+	sig.Register("kafka", func(ctx context.Context) error { return errFlush })
+	sig.Register("db", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	m := NewManager(
+		WithTerminateSignals(sig),
+		WithTimeout(200*time.Millisecond),
+		WithPreStopFunc(func(ctx context.Context) error { return errWait }),
+	)
+	m.Shutdown("test")
+
+	_, err := m.Wait()
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.ErrorIs(t, err, errFlush)
+	assert.ErrorIs(t, err, errWait)
+
+	var te *TimeoutError
+	assert.ErrorAs(t, err, &te)
+	assert.Equal(t, []string{"db"}, te.Pending)
+}
+
+func TestManager_TimeoutKeepsPlanErrors(t *testing.T) {
+	sig := NewTerminateSignal()
+	release := make(chan struct{})
+	defer close(release)
+	sig.Register("http", func(ctx context.Context) error { return nil }, Before("nope"))
+	sig.Register("db", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	m := NewManager(WithTerminateSignals(sig), WithTimeout(100*time.Millisecond))
+	m.Shutdown("test")
+
+	_, err := m.Wait()
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.ErrorIs(t, err, ErrUnknownDependency)
+}
+
+func TestManager_TimeoutKeepsWorkerErrors(t *testing.T) {
+	sig := NewTerminateSignal()
+	errWorker := errors.New("worker failed")
+	release := make(chan struct{})
+	defer close(release)
+	assert.NoError(t, sig.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return errWorker
+	}))
+	sig.Register("db", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	m := NewManager(WithTerminateSignals(sig), WithTimeout(100*time.Millisecond))
+	m.Shutdown("test")
+
+	_, err := m.Wait()
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.ErrorIs(t, err, errWorker)
+}
+
+func TestManager_TimeoutSkipsLaterPhases(t *testing.T) {
+	sig := NewTerminateSignal()
+	release := make(chan struct{})
+	ran := make(chan struct{}, 1)
+	sig.Register("stuck", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	sig.Register("db", func(ctx context.Context) error {
+		ran <- struct{}{}
+		return nil
+	}, WithPhase(1))
+
+	m := NewManager(WithTerminateSignals(sig), WithTimeout(100*time.Millisecond))
+	m.Shutdown("test")
+
+	_, err := m.Wait()
+	var te *TimeoutError
+	assert.ErrorAs(t, err, &te)
+	assert.Equal(t, []string{"stuck"}, te.Pending)
+
+	close(release)
+	<-sig.Done()
+	assert.Len(t, ran, 0)
+	assert.Equal(t, "db", sig.Report()[1].Name)
+	assert.Equal(t, OutcomeSkipped, sig.Report()[1].Outcome)
+}
+
+func TestManager_ForceExitAfterShutdown(t *testing.T) {
+	codes := make(chan int, 1)
+	buf := bytes.Buffer{}
+	osExit = func(code int) { codes <- code }
+	stderr = &buf
+	defer func() {
+		osExit = os.Exit
+		stderr = os.Stderr
+	}()
+
+	src := NewManualSignalSource()
+	sig := NewTerminateSignal()
+	started := make(chan struct{})
+	block := make(chan struct{})
+	sig.Register("kafka", func(ctx context.Context) error {
+		close(started)
+		<-block
+		return nil
+	})
+
+	m := NewManager(WithSignalSource(src), WithForceExit(2, 7), WithTerminateSignals(sig))
+	m.Start()
+	m.Shutdown("lease lost")
+	<-started
+
+	assert.True(t, src.Fire(syscall.SIGINT))
+	select {
+	case <-codes:
+		assert.Fail(t, "forced exit after the first signal")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.True(t, src.Fire(syscall.SIGTERM))
+	select {
+	case code := <-codes:
+		assert.Equal(t, 7, code)
+	case <-time.After(time.Second):
+		assert.Fail(t, "forced exit was not triggered")
+	}
+
+	close(block)
+	_, err := m.Wait()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "received 2 signals [interrupt, terminated]")
+	assert.Contains(t, buf.String(), "pending handles: [kafka]")
+}
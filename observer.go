@@ -0,0 +1,116 @@
+package gs
+
+import (
+	"os"
+	"time"
+)
+
+// Observer 接口用于观察关闭过程中的事件，可以用来接入日志、指标和链路追踪，而不需要包装每一个处理函数
+// 同一个 TerminateSignal 中的处理函数可能并发执行，因此实现必须是并发安全的
+// The Observer interface is used to observe the events during the shutdown, it can be used to connect logging, metrics and tracing without wrapping every handle function
+// Handle functions in the same TerminateSignal may run concurrently, so implementations must be safe for concurrent use
+type Observer interface {
+	// OnSignalReceived 在收到关闭信号时调用，以编程方式触发或者 context 取消时 sig 为 nil，reason 为关闭原因
+	// OnSignalReceived is called when the shutdown signal is received, sig is nil when triggered programmatically or by the cancellation of the context, reason is the shutdown reason
+	OnSignalReceived(sig os.Signal, reason string)
+
+	// OnShutdownStart 在关闭开始时调用，早于关闭前的等待和任何处理函数
+	// OnShutdownStart is called when the shutdown starts, before the pre-stop wait and any handle function
+	OnShutdownStart()
+
+	// OnHandleStart 在处理函数开始执行时调用
+	// OnHandleStart is called when a handle function starts
+	OnHandleStart(name string)
+
+	// OnHandleDone 在处理函数执行完成时调用，err 与 HandleReport.Err 相同
+	// OnHandleDone is called when a handle function completes, err is the same as HandleReport.Err
+	OnHandleDone(name string, duration time.Duration, err error)
+
+	// OnShutdownComplete 在关闭完成时调用，参数与等待函数的返回值相同
+	// OnShutdownComplete is called when the shutdown completes, the parameters are the same as the return values of the waiting functions
+	OnShutdownComplete(report *ShutdownReport, err error)
+
+	// OnTimeout 在关闭操作超过截止时间时调用，pending 为仍在运行的处理函数名称
+	// OnTimeout is called when the shutdown exceeds the deadline, pending are the names of the handle functions still running
+	OnTimeout(pending []string)
+}
+
+// NopObserver 是一个什么都不做的 Observer，嵌入到自定义的 Observer 中后只需要实现关心的方法
+// NopObserver is an Observer that does nothing, after embedding it into a custom Observer only the methods of interest need to be implemented
+type NopObserver struct{}
+
+// OnSignalReceived 什么都不做
+// OnSignalReceived does nothing
+func (NopObserver) OnSignalReceived(os.Signal, string) {}
+
+// OnShutdownStart 什么都不做
+// OnShutdownStart does nothing
+func (NopObserver) OnShutdownStart() {}
+
+// OnHandleStart 什么都不做
+// OnHandleStart does nothing
+func (NopObserver) OnHandleStart(string) {}
+
+// OnHandleDone 什么都不做
+// OnHandleDone does nothing
+func (NopObserver) OnHandleDone(string, time.Duration, error) {}
+
+// OnShutdownComplete 什么都不做
+// OnShutdownComplete does nothing
+func (NopObserver) OnShutdownComplete(*ShutdownReport, error) {}
+
+// OnTimeout 什么都不做
+// OnTimeout does nothing
+func (NopObserver) OnTimeout([]string) {}
+
+// observers 将事件依次分发给多个 Observer
+// observers dispatches the events to multiple Observers in order
+type observers []Observer
+
+// OnSignalReceived 在收到关闭信号时将事件分发给所有的 Observer
+// OnSignalReceived dispatches the event to all Observers when the shutdown signal is received
+func (o observers) OnSignalReceived(sig os.Signal, reason string) {
+	for _, obs := range o {
+		obs.OnSignalReceived(sig, reason)
+	}
+}
+
+// OnShutdownStart 在关闭开始时将事件分发给所有的 Observer
+// OnShutdownStart dispatches the event to all Observers when the shutdown starts
+func (o observers) OnShutdownStart() {
+	for _, obs := range o {
+		obs.OnShutdownStart()
+	}
+}
+
+// OnHandleStart 在处理函数开始执行时将事件分发给所有的 Observer
+// OnHandleStart dispatches the event to all Observers when a handle function starts
+func (o observers) OnHandleStart(name string) {
+	for _, obs := range o {
+		obs.OnHandleStart(name)
+	}
+}
+
+// OnHandleDone 在处理函数执行完成时将事件分发给所有的 Observer
+// OnHandleDone dispatches the event to all Observers when a handle function completes
+func (o observers) OnHandleDone(name string, duration time.Duration, err error) {
+	for _, obs := range o {
+		obs.OnHandleDone(name, duration, err)
+	}
+}
+
+// OnShutdownComplete 在关闭完成时将事件分发给所有的 Observer
+// OnShutdownComplete dispatches the event to all Observers when the shutdown completes
+func (o observers) OnShutdownComplete(report *ShutdownReport, err error) {
+	for _, obs := range o {
+		obs.OnShutdownComplete(report, err)
+	}
+}
+
+// OnTimeout 在关闭超时时将事件分发给所有的 Observer
+// OnTimeout dispatches the event to all Observers when the shutdown times out
+func (o observers) OnTimeout(pending []string) {
+	for _, obs := range o {
+		obs.OnTimeout(pending)
+	}
+}
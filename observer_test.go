@@ -0,0 +1,100 @@
+package gs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testObserver struct {
+	NopObserver
+	mu     sync.Mutex
+	events []string
+}
+
+func (o *testObserver) record(format string, args ...interface{}) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, fmt.Sprintf(format, args...))
+}
+
+func (o *testObserver) OnSignalReceived(sig os.Signal, reason string) {
+	o.record("signal %v %s", sig, reason)
+}
+
+func (o *testObserver) OnShutdownStart() {
+	o.record("start")
+}
+
+func (o *testObserver) OnHandleStart(name string) {
+	o.record("handle start %s", name)
+}
+
+func (o *testObserver) OnHandleDone(name string, duration time.Duration, err error) {
+	o.record("handle done %s %v", name, err != nil)
+}
+
+func (o *testObserver) OnShutdownComplete(report *ShutdownReport, err error) {
+	o.record("complete %d %v", len(report.Handles), err != nil)
+}
+
+func (o *testObserver) OnTimeout(pending []string) {
+	o.record("timeout %v", pending)
+}
+
+func TestObserver(t *testing.T) {
+	src := NewManualSignalSource()
+	obs := &testObserver{}
+	sig := NewTerminateSignal()
+	sig.Register("http", func(ctx context.Context) error { return nil })
+	sig.Register("db", func(ctx context.Context) error { return errors.New("db failed") }, After("http"))
+
+	m := NewManager(WithSignalSource(src), WithTerminateSignals(sig), WithObserver(obs, nil))
+	m.Start()
+	assert.True(t, src.Fire(syscall.SIGTERM))
+	_, err := m.Wait()
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{
+		"signal terminated terminated",
+		"start",
+		"handle start http",
+		"handle done http false",
+		"handle start db",
+		"handle done db true",
+		"complete 2 true",
+	}, obs.events)
+}
+
+func TestObserver_Timeout(t *testing.T) {
+	obs := &testObserver{}
+	sig := NewTerminateSignal()
+	release := make(chan struct{})
+	defer close(release)
+	sig.Register("stuck", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	m := NewManager(WithSignalSource(NewManualSignalSource()), WithTerminateSignals(sig), WithTimeout(100*time.Millisecond), WithObserver(obs))
+	m.Shutdown("lease lost")
+	_, err := m.Wait()
+	assert.ErrorIs(t, err, ErrTimeout)
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	assert.Equal(t, []string{
+		"signal <nil> lease lost",
+		"start",
+		"handle start stuck",
+		"timeout [stuck]",
+		"complete 1 true",
+	}, obs.events)
+}
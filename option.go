@@ -0,0 +1,290 @@
+package gs
+
+import (
+	"context"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultSignals 是默认监听的系统信号：SIGINT、SIGTERM 和 SIGQUIT
+// DefaultSignals are the system signals listened to by default: SIGINT, SIGTERM and SIGQUIT
+var DefaultSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT}
+
+// config 结构体包含了等待函数的所有配置项
+// The config struct contains all the configuration items of the waiting functions
+type config struct {
+	// signals 是需要监听的系统信号
+	// signals are the system signals to listen to
+	signals []os.Signal
+
+	// handlers 是非终止信号的处理函数，收到这些信号时执行处理函数而不触发关闭
+	// handlers are the handle functions of non-terminating signals, when these signals are received the handle functions are executed without triggering the shutdown
+	handlers map[os.Signal][]func()
+
+	// handlersMu 是一个 sync.Mutex 实例，用于保护 handlers 和 listener 的并发访问，Start 之后仍然可以注册处理函数
+	// handlersMu is a sync.Mutex instance, used to protect concurrent access to handlers and listener, handle functions can still be registered after Start
+	handlersMu sync.Mutex
+
+	// listener 是当前接收非终止信号的通道，为 nil 表示还没有开始监听或者已经停止监听
+	// listener is the channel currently receiving the non-terminating signals, nil means listening has not started yet or has stopped
+	listener chan<- os.Signal
+
+	// source 是系统信号的来源
+	// source is the source of system signals
+	source SignalSource
+
+	// mode 是关闭模式
+	// mode is the close mode
+	mode CloseType
+
+	// sigs 是需要关闭的 TerminateSignal 实例
+	// sigs are the TerminateSignal instances to be closed
+	sigs []*TerminateSignal
+
+	// timeout 是关闭操作的截止时间，小于等于 0 表示没有截止时间
+	// timeout is the deadline of the shutdown, less than or equal to 0 means no deadline
+	timeout time.Duration
+
+	// timeoutGrace 是截止时间到达后等待处理函数执行回退操作并记录结果的宽限期，小于等于 0 表示不等待
+	// timeoutGrace is the grace period to wait for the handle functions to run their fallback and record their results after the deadline, less than or equal to 0 means no wait
+	timeoutGrace time.Duration
+
+	// forceExitCount 是触发强制退出的信号总数，小于等于 1 表示不启用强制退出
+	// forceExitCount is the total number of signals that triggers the forced exit, less than or equal to 1 means forced exit is disabled
+	forceExitCount int
+
+	// forceExitCode 是强制退出时的退出码
+	// forceExitCode is the exit code of the forced exit
+	forceExitCode int
+
+	// ctx 是父 context，它被取消时与收到系统信号一样触发关闭
+	// ctx is the parent context, when it is cancelled the shutdown is triggered just like receiving a system signal
+	ctx context.Context
+
+	// preStopDelay 是收到关闭信号后、执行任何处理函数之前的等待时间
+	// preStopDelay is the wait time after the shutdown signal is received and before any handle function runs
+	preStopDelay time.Duration
+
+	// preStopFuncs 是执行任何处理函数之前依次调用的等待函数
+	// preStopFuncs are the wait functions called in order before any handle function runs
+	preStopFuncs []func(ctx context.Context) error
+
+	// observers 是关闭过程中事件的观察者
+	// observers are the observers of the events during the shutdown
+	observers observers
+
+	// probes 是与关闭状态绑定的探针
+	// probes are the probes bound to the shutdown state
+	probes []*Probe
+
+	// trigger 是以编程方式触发关闭的通道，由 Manager 设置
+	// trigger is the channel that triggers the shutdown programmatically, set by the Manager
+	trigger <-chan string
+}
+
+// Option 是一个用于修改配置的函数类型
+// Option is a function type used to modify the configuration
+type Option func(*config)
+
+// newConfig 创建一个带有默认值的配置，并应用所有的选项
+// newConfig creates a configuration with default values and applies all options
+func newConfig(opts ...Option) *config {
+	// 初始化默认配置
+	// Initialize the default configuration
+	c := &config{
+		signals:  DefaultSignals,
+		source:   osSignalSource{},
+		handlers: make(map[os.Signal][]func()),
+		mode:     ASyncClose,
+		sigs:     make([]*TerminateSignal, 0),
+		ctx:      context.Background(),
+	}
+
+	// 依次应用所有的选项
+	// Apply all options in order
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+
+	// 返回配置
+	// Return the configuration
+	return c
+}
+
+// WithSignals 设置需要监听的系统信号，未传入任何信号时保持默认值
+// WithSignals sets the system signals to listen to, the default value is kept when no signal is passed
+func WithSignals(signals ...os.Signal) Option {
+	return func(c *config) {
+		if len(signals) > 0 {
+			c.signals = signals
+		}
+	}
+}
+
+// WithSignalHandler 注册一个非终止信号的处理函数，例如 SIGHUP 重新加载配置，收到该信号时执行 fn 而不触发关闭
+// 如果 sig 同时在需要监听的系统信号中，那么它不再触发关闭
+// WithSignalHandler registers a handle function for a non-terminating signal, e.g. SIGHUP to reload the configuration, when the signal is received fn is executed without triggering the shutdown
+// If sig is also in the system signals to listen to, then it no longer triggers the shutdown
+func WithSignalHandler(sig os.Signal, fn func()) Option {
+	return func(c *config) {
+		if sig != nil && fn != nil {
+			c.addSignalHandler(sig, fn)
+		}
+	}
+}
+
+// addSignalHandler 注册一个非终止信号的处理函数，如果已经开始监听，那么立即让监听的通道接收该信号
+// addSignalHandler registers a handle function for a non-terminating signal, if listening has started, then the listening channel receives the signal immediately
+func (c *config) addSignalHandler(sig os.Signal, fn func()) {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+
+	c.handlers[sig] = append(c.handlers[sig], fn)
+	if c.listener != nil {
+		c.source.Notify(c.listener, sig)
+	}
+}
+
+// signalHandlers 返回 sig 的处理函数，以及 sig 是否是非终止信号
+// signalHandlers returns the handle functions of sig, and whether sig is a non-terminating signal
+func (c *config) signalHandlers(sig os.Signal) ([]func(), bool) {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+
+	fns, ok := c.handlers[sig]
+	return fns, ok
+}
+
+// setListener 让 ch 接收所有的非终止信号，之后注册的非终止信号也会发送到 ch，ch 为 nil 时不再为新注册的信号订阅任何通道
+// setListener makes ch receive all non-terminating signals, signals registered later are also sent to ch, when ch is nil no channel is subscribed for newly registered signals
+func (c *config) setListener(ch chan<- os.Signal) {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+
+	// 没有任何非终止信号时不能调用 Notify，否则会订阅所有的信号
+	// Notify must not be called without any non-terminating signal, otherwise all signals are subscribed
+	if ch != nil && len(c.handlers) > 0 {
+		sigs := make([]os.Signal, 0, len(c.handlers))
+		for sig := range c.handlers {
+			sigs = append(sigs, sig)
+		}
+		c.source.Notify(ch, sigs...)
+	}
+	c.listener = ch
+}
+
+// WithSignalSource 设置系统信号的来源，默认使用 os/signal，测试中可以使用 ManualSignalSource
+// WithSignalSource sets the source of system signals, os/signal is used by default, ManualSignalSource can be used in tests
+func WithSignalSource(source SignalSource) Option {
+	return func(c *config) {
+		if source != nil {
+			c.source = source
+		}
+	}
+}
+
+// WithCloseMode 设置关闭模式
+// WithCloseMode sets the close mode
+func WithCloseMode(mode CloseType) Option {
+	return func(c *config) {
+		c.mode = mode
+	}
+}
+
+// WithTerminateSignals 设置在收到信号后需要关闭的 TerminateSignal 实例
+// WithTerminateSignals sets the TerminateSignal instances to be closed after the signal is received
+func WithTerminateSignals(sigs ...*TerminateSignal) Option {
+	return func(c *config) {
+		c.sigs = append(c.sigs, sigs...)
+	}
+}
+
+// WithTimeout 设置关闭操作的截止时间，超时后等待函数返回 TimeoutError，没有设置 WithTimeoutGrace 时立即返回
+// WithTimeout sets the deadline of the shutdown, the waiting function returns a TimeoutError after the timeout, immediately when WithTimeoutGrace is not set
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+	}
+}
+
+// WithTimeoutGrace 设置截止时间到达后的宽限期，处理函数可以在宽限期内执行回退操作（例如 RegisterHTTPServer 调用 srv.Close），
+// 宽限期内完成的处理函数的错误也会被汇总，等待函数最多在截止时间之后 grace 返回，默认不等待
+// WithTimeoutGrace sets the grace period after the deadline, the handle functions can run their fallback within the grace period (e.g. RegisterHTTPServer calls srv.Close),
+// the errors of the handle functions completed within the grace period are aggregated too, the waiting function returns at most grace after the deadline, no wait by default
+func WithTimeoutGrace(grace time.Duration) Option {
+	return func(c *config) {
+		c.timeoutGrace = grace
+	}
+}
+
+// WithForceExit 启用强制退出：第一个信号触发优雅关闭，在关闭过程中收到的信号总数达到 count 时（例如再按一次 Ctrl-C），
+// 跳过剩余的处理函数，输出仍在运行的处理函数并以 code 退出进程，count 小于 2 时按 2 处理
+// WithForceExit enables the forced exit: the first signal triggers the graceful shutdown, when the total number of signals received during the shutdown reaches count (e.g. pressing Ctrl-C again),
+// the remaining handle functions are skipped, the still running handle functions are printed and the process exits with code, count less than 2 is treated as 2
+func WithForceExit(count int, code int) Option {
+	return func(c *config) {
+		if count < 2 {
+			count = 2
+		}
+		c.forceExitCount = count
+		c.forceExitCode = code
+	}
+}
+
+// WithContext 设置父 context，当它被取消时与收到系统信号一样触发关闭
+// WithContext sets the parent context, when it is cancelled the shutdown is triggered just like receiving a system signal
+func WithContext(ctx context.Context) Option {
+	return func(c *config) {
+		if ctx != nil {
+			c.ctx = ctx
+		}
+	}
+}
+
+// WithProbe 将探针与关闭状态绑定，收到关闭信号时探针进入 StateStopping，所有处理函数执行完成后进入 StateStopped
+// WithProbe binds the probe to the shutdown state, the probe enters StateStopping when the shutdown signal is received and StateStopped after all handle functions have completed
+func WithProbe(probe *Probe) Option {
+	return func(c *config) {
+		if probe != nil {
+			c.probes = append(c.probes, probe)
+		}
+	}
+}
+
+// WithPreStopDelay 设置关闭前的等待时间：收到关闭信号后探针立即进入未就绪状态，等待 delay 之后才执行处理函数，
+// 让负载均衡器有时间摘除流量，等待时间计入 WithTimeout 设置的截止时间
+// WithPreStopDelay sets the pre-stop delay: after the shutdown signal is received the probes become not ready immediately, and the handle functions run only after delay,
+// giving the load balancer time to drain the traffic, the delay counts towards the deadline set by WithTimeout
+func WithPreStopDelay(delay time.Duration) Option {
+	return func(c *config) {
+		c.preStopDelay = delay
+	}
+}
+
+// WithPreStopFunc 添加一个关闭前的等待函数，它在关闭前的等待时间之后、执行任何处理函数之前调用，例如等待在途请求数降为 0，
+// ctx 携带了剩余的截止时间，返回的错误会被汇总，但不会阻止处理函数执行
+// WithPreStopFunc adds a pre-stop wait function, it is called after the pre-stop delay and before any handle function runs, e.g. to wait for the in-flight requests to drop to 0,
+// ctx carries the remaining deadline, the returned error is aggregated but does not prevent the handle functions from running
+func WithPreStopFunc(fn func(ctx context.Context) error) Option {
+	return func(c *config) {
+		if fn != nil {
+			c.preStopFuncs = append(c.preStopFuncs, fn)
+		}
+	}
+}
+
+// WithObserver 添加关闭过程中事件的观察者，可以多次调用以添加多个观察者，事件按添加的顺序分发
+// WithObserver adds observers of the events during the shutdown, it can be called multiple times to add multiple observers, the events are dispatched in the order of addition
+func WithObserver(obs ...Observer) Option {
+	return func(c *config) {
+		for _, o := range obs {
+			if o != nil {
+				c.observers = append(c.observers, o)
+			}
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package gs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// plan 结构体是关闭计划，包含了按阶段分组并按依赖关系排序的处理函数
+// The plan struct is the shutdown plan, containing the handle functions grouped by phase and sorted by dependency
+type plan struct {
+	// phases 是按阶段从小到大分组的处理函数，每个阶段内按依赖关系拓扑排序
+	// phases are the handle functions grouped by phase in ascending order, topologically sorted by dependency within each phase
+	phases [][]*handle
+
+	// preds 是每个处理函数在同一阶段内必须等待的前置处理函数
+	// preds are the predecessor handle functions that each handle function must wait for within the same phase
+	preds map[*handle][]*handle
+
+	// errs 是生成关闭计划时发现的错误，例如未知的依赖和循环依赖
+	// errs are the errors found while generating the shutdown plan, such as unknown dependencies and dependency cycles
+	errs []error
+}
+
+// newPlan 根据处理函数的阶段和依赖关系生成关闭计划
+// 未知的依赖会被忽略，如果存在循环依赖，那么忽略所有的依赖关系，只按阶段和注册顺序关闭，错误会记录在 errs 中
+// newPlan generates the shutdown plan according to the phases and dependencies of the handle functions
+// Unknown dependencies are ignored, if there is a dependency cycle, then all dependencies are ignored and only the phase and registration order are used, the errors are recorded in errs
+func newPlan(handles []*handle) *plan {
+	// 初始化关闭计划
+	// Initialize the shutdown plan
+	p := &plan{
+		phases: planPhases(handles),
+		preds:  make(map[*handle][]*handle),
+		errs:   make([]error, 0),
+	}
+
+	// 建立名称到处理函数的索引
+	// Build an index from name to handle functions
+	byName := make(map[string][]*handle)
+	for _, h := range handles {
+		byName[h.name] = append(byName[h.name], h)
+	}
+
+	// 将所有的依赖关系转换为 "a 必须在 b 之前关闭" 的边
+	// Convert all dependencies into edges "a must close before b"
+	cyclic := false
+	addEdge := func(a, b *handle) {
+		switch {
+		// 不同阶段之间的顺序由阶段决定，与阶段顺序一致的依赖已经满足
+		// The order between different phases is determined by the phase, dependencies consistent with the phase order are already satisfied
+		case a.phase < b.phase:
+
+		// 与阶段顺序冲突的依赖无法满足，视为循环依赖
+		// Dependencies that conflict with the phase order cannot be satisfied and are treated as a dependency cycle
+		case a.phase > b.phase:
+			cyclic = true
+			p.errs = append(p.errs, fmt.Errorf("%w: %s must close before %s, but it is in a later phase", ErrDependencyCycle, a.name, b.name))
+
+		// 同一个处理函数不能依赖自己
+		// A handle function cannot depend on itself
+		case a == b:
+			cyclic = true
+			p.errs = append(p.errs, fmt.Errorf("%w: %s depends on itself", ErrDependencyCycle, a.name))
+
+		// 同一阶段内的依赖记录为前置处理函数
+		// Dependencies within the same phase are recorded as predecessors
+		default:
+			p.preds[b] = append(p.preds[b], a)
+		}
+	}
+	for _, h := range handles {
+		for _, name := range h.before {
+			targets, ok := byName[name]
+			if !ok {
+				p.errs = append(p.errs, fmt.Errorf("%w: %s must close before %s", ErrUnknownDependency, h.name, name))
+				continue
+			}
+			for _, t := range targets {
+				addEdge(h, t)
+			}
+		}
+		for _, name := range h.after {
+			targets, ok := byName[name]
+			if !ok {
+				p.errs = append(p.errs, fmt.Errorf("%w: %s must close after %s", ErrUnknownDependency, h.name, name))
+				continue
+			}
+			for _, t := range targets {
+				addEdge(t, h)
+			}
+		}
+	}
+
+	// 如果没有与阶段冲突的依赖，那么对每个阶段进行拓扑排序
+	// If there are no dependencies conflicting with the phases, then topologically sort each phase
+	if !cyclic {
+		sorted := make([][]*handle, 0, len(p.phases))
+		for _, phase := range p.phases {
+			order, rest := p.sortPhase(phase)
+			if len(rest) > 0 {
+				cyclic = true
+				p.errs = append(p.errs, fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(handleNames(rest), ", ")))
+				break
+			}
+			sorted = append(sorted, order)
+		}
+		if !cyclic {
+			p.phases = sorted
+		}
+	}
+
+	// 如果存在循环依赖，那么忽略所有的依赖关系，避免死锁
+	// If there is a dependency cycle, then ignore all dependencies to avoid deadlock
+	if cyclic {
+		p.preds = make(map[*handle][]*handle)
+	}
+
+	// 返回关闭计划
+	// Return the shutdown plan
+	return p
+}
+
+// sortPhase 对同一阶段内的处理函数进行稳定的拓扑排序，返回排序结果和因循环依赖无法排序的处理函数
+// sortPhase performs a stable topological sort of the handle functions within the same phase, returns the sorted result and the handle functions that cannot be sorted due to a dependency cycle
+func (p *plan) sortPhase(phase []*handle) ([]*handle, []*handle) {
+	// 计算每个处理函数的入度和后继处理函数
+	// Calculate the in-degree and successors of each handle function
+	indegree := make(map[*handle]int, len(phase))
+	succs := make(map[*handle][]*handle, len(phase))
+	for _, h := range phase {
+		indegree[h] += len(p.preds[h])
+		for _, pred := range p.preds[h] {
+			succs[pred] = append(succs[pred], h)
+		}
+	}
+
+	// 按注册顺序反复选出入度为 0 的处理函数
+	// Repeatedly pick the handle functions with zero in-degree in registration order
+	order := make([]*handle, 0, len(phase))
+	visited := make(map[*handle]bool, len(phase))
+	for len(order) < len(phase) {
+		progress := false
+		for _, h := range phase {
+			if visited[h] || indegree[h] > 0 {
+				continue
+			}
+			visited[h] = true
+			progress = true
+			order = append(order, h)
+			for _, succ := range succs[h] {
+				indegree[succ]--
+			}
+		}
+
+		// 如果没有任何进展，那么剩余的处理函数存在循环依赖
+		// If there is no progress, then the remaining handle functions have a dependency cycle
+		if !progress {
+			break
+		}
+	}
+
+	// 收集因循环依赖无法排序的处理函数
+	// Collect the handle functions that cannot be sorted due to a dependency cycle
+	rest := make([]*handle, 0)
+	for _, h := range phase {
+		if !visited[h] {
+			rest = append(rest, h)
+		}
+	}
+
+	// 返回排序结果和剩余的处理函数
+	// Return the sorted result and the remaining handle functions
+	return order, rest
+}
+
+// planPhases 将处理函数按关闭阶段从小到大分组，同一阶段内保持注册顺序
+// planPhases groups the handle functions by shutdown phase in ascending order, keeping the registration order within the same phase
+func planPhases(handles []*handle) [][]*handle {
+	// 复制一份处理函数切片，避免修改原始的注册顺序
+	// Copy the handle slice to avoid modifying the original registration order
+	sorted := make([]*handle, len(handles))
+	copy(sorted, handles)
+
+	// 按关闭阶段稳定排序
+	// Stable sort by shutdown phase
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].phase < sorted[j].phase
+	})
+
+	// 将相同阶段的处理函数放入同一个分组
+	// Put handle functions of the same phase into the same group
+	phases := make([][]*handle, 0)
+	for i, h := range sorted {
+		if i == 0 || h.phase != sorted[i-1].phase {
+			phases = append(phases, make([]*handle, 0))
+		}
+		phases[len(phases)-1] = append(phases[len(phases)-1], h)
+	}
+
+	// 返回分组后的处理函数
+	// Return the grouped handle functions
+	return phases
+}
+
+// handleNames 返回处理函数的名称
+// handleNames returns the names of the handle functions
+func handleNames(handles []*handle) []string {
+	names := make([]string, 0, len(handles))
+	for _, h := range handles {
+		names = append(names, h.name)
+	}
+	return names
+}
@@ -0,0 +1,103 @@
+package gs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlan_Dependencies(t *testing.T) {
+	sig := NewTerminateSignal()
+	assert.NotNil(t, sig, "signal is nil")
+	sleep := func(ctx context.Context) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}
+	sig.Register("db", sleep)
+	sig.Register("http", sleep, Before("db", "cache"))
+	sig.Register("cache", sleep)
+	sig.Register("metrics", sleep)
+	sig.Register("queue", sleep, After("http"), Before("db"))
+
+	start := time.Now()
+	assert.NoError(t, sig.Close(nil))
+	assert.Less(t, time.Since(start), 400*time.Millisecond)
+
+	reports := make(map[string]HandleReport)
+	for _, r := range sig.Report() {
+		reports[r.Name] = r
+	}
+	assert.False(t, reports["db"].Start.Before(reports["http"].End))
+	assert.False(t, reports["db"].Start.Before(reports["queue"].End))
+	assert.False(t, reports["cache"].Start.Before(reports["http"].End))
+	assert.False(t, reports["queue"].Start.Before(reports["http"].End))
+	assert.True(t, reports["metrics"].Start.Before(reports["http"].End))
+}
+
+func TestPlan_Dependencies_Sync(t *testing.T) {
+	sig := NewTerminateSignal()
+	assert.NotNil(t, sig, "signal is nil")
+	rec := newTestRecorder()
+	sig.Register("db", rec.handle("db"))
+	sig.Register("http", rec.handle("http"), Before("db"))
+	sig.Register("cache", rec.handle("cache"), After("db"))
+
+	assert.NoError(t, sig.SyncClose(nil))
+	assert.Equal(t, []string{"http", "db", "cache"}, rec.order())
+}
+
+func TestPlan_Cycle(t *testing.T) {
+	sig := NewTerminateSignal()
+	assert.NotNil(t, sig, "signal is nil")
+	count := 0
+	mu := sync.Mutex{}
+	inc := func(ctx context.Context) error {
+		mu.Lock()
+		count++
+		mu.Unlock()
+		return nil
+	}
+	sig.Register("a", inc, Before("b"))
+	sig.Register("b", inc, Before("c"))
+	sig.Register("c", inc, Before("a"))
+	sig.Register("d", inc)
+
+	err := sig.Close(nil)
+	assert.True(t, errors.Is(err, ErrDependencyCycle))
+	assert.Contains(t, err.Error(), "a, b, c")
+	assert.Equal(t, 4, count)
+}
+
+func TestPlan_PhaseConflict(t *testing.T) {
+	sig := NewTerminateSignal()
+	assert.NotNil(t, sig, "signal is nil")
+	nop := func(ctx context.Context) error { return nil }
+	sig.Register("http", nop, WithPhase(1), Before("db"))
+	sig.Register("db", nop)
+
+	err := sig.Close(nil)
+	assert.True(t, errors.Is(err, ErrDependencyCycle))
+	for _, r := range sig.Report() {
+		assert.Equal(t, OutcomeOK, r.Outcome)
+	}
+}
+
+func TestPlan_UnknownDependency(t *testing.T) {
+	sig := NewTerminateSignal()
+	assert.NotNil(t, sig, "signal is nil")
+	nop := func(ctx context.Context) error { return nil }
+	sig.Register("http", nop, Before("db", "postgres"))
+	sig.Register("db", nop)
+
+	err := sig.Close(nil)
+	assert.True(t, errors.Is(err, ErrUnknownDependency))
+	assert.False(t, errors.Is(err, ErrDependencyCycle))
+	assert.Contains(t, err.Error(), "postgres")
+	for _, r := range sig.Report() {
+		assert.Equal(t, OutcomeOK, r.Outcome)
+	}
+}
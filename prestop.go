@@ -0,0 +1,45 @@
+package gs
+
+import (
+	"context"
+	"time"
+)
+
+// preStopName 是关闭前的等待函数在错误信息中使用的名称
+// preStopName is the name used by the pre-stop wait functions in error messages
+const preStopName = "pre-stop"
+
+// preStop 函数在执行任何处理函数之前，先等待 cfg.preStopDelay，再依次调用 cfg.preStopFuncs，
+// ctx 结束时立即停止等待，返回等待函数的错误
+// The preStop function waits for cfg.preStopDelay and then calls cfg.preStopFuncs in order before any handle function runs,
+// it stops waiting immediately when ctx is done, and returns the errors of the wait functions
+func preStop(ctx context.Context, cfg *config) []error {
+	errs := make([]error, 0)
+
+	// 等待关闭前的等待时间，截止时间到达时不再等待
+	// Wait for the pre-stop delay, stop waiting when the deadline is reached
+	if cfg.preStopDelay > 0 {
+		timer := time.NewTimer(cfg.preStopDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return errs
+		}
+	}
+
+	// 依次调用等待函数，与处理函数一样在 recover 中执行
+	// Call the wait functions in order, executed under recover just like the handle functions
+	for _, fn := range cfg.preStopFuncs {
+		h := newHandle(preStopName, fn)
+		h.begin()
+		h.finish(h.run(ctx))
+		if h.err != nil {
+			errs = append(errs, h.err)
+		}
+	}
+
+	// 返回等待函数的错误
+	// Return the errors of the wait functions
+	return errs
+}
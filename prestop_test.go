@@ -0,0 +1,65 @@
+package gs
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreStop_Delay(t *testing.T) {
+	src := NewManualSignalSource()
+	probe := NewProbe()
+	sig := NewTerminateSignal()
+
+	var ready bool
+	errWait := errors.New("wait failed")
+	sig.Register("http", func(ctx context.Context) error { return nil })
+
+	m := NewManager(
+		WithSignalSource(src),
+		WithProbe(probe),
+		WithTerminateSignals(sig),
+		WithPreStopDelay(200*time.Millisecond),
+		WithPreStopFunc(func(ctx context.Context) error {
+			ready = probe.Ready()
+			return errWait
+		}),
+	)
+	m.Start()
+	assert.True(t, src.Fire(syscall.SIGTERM))
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, StateStopping, probe.State())
+	assert.NoError(t, sig.GetStopContext().Err())
+	assert.Nil(t, m.Report())
+
+	r, err := m.Wait()
+	assert.ErrorIs(t, err, errWait)
+	assert.False(t, ready)
+	assert.GreaterOrEqual(t, r.Duration, 200*time.Millisecond)
+	assert.Equal(t, OutcomeOK, r.Handles[0].Outcome)
+}
+
+func TestPreStop_Timeout(t *testing.T) {
+	src := NewManualSignalSource()
+	sig := NewTerminateSignal()
+	sig.Register("http", func(ctx context.Context) error { return nil })
+
+	m := NewManager(
+		WithSignalSource(src),
+		WithTerminateSignals(sig),
+		WithTimeout(100*time.Millisecond),
+		WithPreStopDelay(time.Minute),
+	)
+	m.Start()
+	assert.True(t, src.Fire(syscall.SIGTERM))
+
+	start := time.Now()
+	_, err := m.Wait()
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.Less(t, time.Since(start), time.Second)
+}
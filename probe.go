@@ -0,0 +1,126 @@
+package gs
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// State 是一个 int32 类型的别名，用于表示服务的关闭状态
+// State is an alias for int32, used to represent the shutdown state of the service
+type State int32
+
+// 定义了服务的三种关闭状态：运行中、关闭中和已关闭
+// Three shutdown states of the service are defined: running, stopping and stopped
+const (
+	// StateRunning 表示服务正在运行，还没有收到关闭信号
+	// StateRunning indicates that the service is running and has not received a shutdown signal
+	StateRunning State = iota
+
+	// StateStopping 表示服务已经收到关闭信号，处理函数正在执行
+	// StateStopping indicates that the service has received a shutdown signal and the handle functions are running
+	StateStopping
+
+	// StateStopped 表示所有的处理函数已经执行完成
+	// StateStopped indicates that all handle functions have completed
+	StateStopped
+)
+
+// String 返回关闭状态的名称
+// String returns the name of the shutdown state
+func (s State) String() string {
+	switch s {
+	case StateRunning:
+		return "running"
+	case StateStopping:
+		return "stopping"
+	case StateStopped:
+		return "stopped"
+	default:
+		return "unknown"
+	}
+}
+
+// Probe 结构体是一个与关闭状态绑定的 http.Handler，提供 /readyz 和 /livez 探针
+// 收到关闭信号后 /readyz 立即返回 503，让负载均衡器摘除流量，/livez 在所有处理函数执行完成之前一直返回 200
+// The Probe struct is an http.Handler bound to the shutdown state, providing /readyz and /livez probes
+// After the shutdown signal is received /readyz returns 503 immediately so that the load balancer drains the traffic, /livez keeps returning 200 until all handle functions have completed
+type Probe struct {
+	// state 是当前的关闭状态
+	// state is the current shutdown state
+	state atomic.Int32
+}
+
+// NewProbe 创建一个新的 Probe 实例，初始状态为 StateRunning
+// NewProbe creates a new Probe instance, the initial state is StateRunning
+func NewProbe() *Probe {
+	p := &Probe{}
+	p.state.Store(int32(StateRunning))
+	return p
+}
+
+// setState 设置当前的关闭状态
+// setState sets the current shutdown state
+func (p *Probe) setState(state State) {
+	p.state.Store(int32(state))
+}
+
+// State 返回当前的关闭状态
+// State returns the current shutdown state
+func (p *Probe) State() State {
+	return State(p.state.Load())
+}
+
+// Ready 返回服务是否就绪，收到关闭信号后返回 false
+// Ready returns whether the service is ready, returns false after the shutdown signal is received
+func (p *Probe) Ready() bool {
+	return p.State() == StateRunning
+}
+
+// Alive 返回服务是否存活，所有处理函数执行完成后返回 false
+// Alive returns whether the service is alive, returns false after all handle functions have completed
+func (p *Probe) Alive() bool {
+	return p.State() != StateStopped
+}
+
+// ReadinessHandler 返回就绪探针的 http.Handler，可以挂载到任意路径
+// ReadinessHandler returns the http.Handler of the readiness probe, it can be mounted on any path
+func (p *Probe) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeProbe(w, p.Ready(), p.State())
+	})
+}
+
+// LivenessHandler 返回存活探针的 http.Handler，可以挂载到任意路径
+// LivenessHandler returns the http.Handler of the liveness probe, it can be mounted on any path
+func (p *Probe) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeProbe(w, p.Alive(), p.State())
+	})
+}
+
+// ServeHTTP 根据请求路径分发到 /readyz 和 /livez 探针，路径必须完全匹配，其他路径返回 404
+// 挂载到带前缀的路径时，使用 http.StripPrefix 去掉前缀，例如 mux.Handle("/health/", http.StripPrefix("/health", probe))
+// ServeHTTP dispatches to the /readyz and /livez probes according to the request path, the path must match exactly, other paths return 404
+// When mounted on a prefixed path, use http.StripPrefix to remove the prefix, e.g. mux.Handle("/health/", http.StripPrefix("/health", probe))
+func (p *Probe) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/readyz":
+		p.ReadinessHandler().ServeHTTP(w, r)
+	case "/livez":
+		p.LivenessHandler().ServeHTTP(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// writeProbe 写入探针的响应，ok 为 true 时返回 200，否则返回 503，响应体为当前的关闭状态
+// writeProbe writes the response of the probe, returns 200 when ok is true, otherwise returns 503, the response body is the current shutdown state
+func writeProbe(w http.ResponseWriter, ok bool, state State) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if ok {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_, _ = w.Write([]byte(state.String()))
+}
@@ -0,0 +1,57 @@
+package gs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func probeStatus(p *Probe, path string) int {
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w.Code
+}
+
+func TestProbe_States(t *testing.T) {
+	src := NewManualSignalSource()
+	probe := NewProbe()
+	sig := NewTerminateSignal()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	sig.Register("drain", func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+
+	assert.Equal(t, StateRunning, probe.State())
+	assert.Equal(t, http.StatusOK, probeStatus(probe, "/readyz"))
+	assert.Equal(t, http.StatusOK, probeStatus(probe, "/livez"))
+	assert.Equal(t, http.StatusNotFound, probeStatus(probe, "/metrics"))
+	assert.Equal(t, http.StatusNotFound, probeStatus(probe, "/api/v1/readyz"))
+
+	w := httptest.NewRecorder()
+	http.StripPrefix("/health", probe).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/livez", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	m := NewManager(WithSignalSource(src), WithProbe(probe), WithTerminateSignals(sig))
+	m.Start()
+	assert.True(t, src.Fire(syscall.SIGTERM))
+
+	<-started
+	assert.Equal(t, StateStopping, probe.State())
+	assert.Equal(t, http.StatusServiceUnavailable, probeStatus(probe, "/readyz"))
+	assert.Equal(t, http.StatusOK, probeStatus(probe, "/livez"))
+
+	close(release)
+	_, err := m.Wait()
+	assert.NoError(t, err)
+	assert.Equal(t, StateStopped, probe.State())
+	assert.Equal(t, http.StatusServiceUnavailable, probeStatus(probe, "/readyz"))
+	assert.Equal(t, http.StatusServiceUnavailable, probeStatus(probe, "/livez"))
+}
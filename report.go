@@ -0,0 +1,141 @@
+package gs
+
+import (
+	"os"
+	"time"
+)
+
+// Outcome 是一个 int8 类型的别名，用于表示处理函数的执行结果
+// Outcome is an alias for int8, used to represent the result of a handle function
+type Outcome int8
+
+// 定义了处理函数的所有执行结果
+// All results of a handle function are defined
+const (
+	// OutcomePending 表示处理函数还没有执行完成
+	// OutcomePending indicates that the handle function has not completed yet
+	OutcomePending Outcome = iota
+
+	// OutcomeOK 表示处理函数执行成功
+	// OutcomeOK indicates that the handle function succeeded
+	OutcomeOK
+
+	// OutcomeError 表示处理函数返回了错误
+	// OutcomeError indicates that the handle function returned an error
+	OutcomeError
+
+	// OutcomePanic 表示处理函数发生了 panic
+	// OutcomePanic indicates that the handle function panicked
+	OutcomePanic
+
+	// OutcomeTimeout 表示处理函数超过了自己的超时时间
+	// OutcomeTimeout indicates that the handle function exceeded its own timeout
+	OutcomeTimeout
+
+	// OutcomeSkipped 表示处理函数因为 context 已经超时而没有执行
+	// OutcomeSkipped indicates that the handle function was not executed because the context had already timed out
+	OutcomeSkipped
+)
+
+// String 返回执行结果的名称
+// String returns the name of the result
+func (o Outcome) String() string {
+	switch o {
+	case OutcomePending:
+		return "pending"
+	case OutcomeOK:
+		return "ok"
+	case OutcomeError:
+		return "error"
+	case OutcomePanic:
+		return "panic"
+	case OutcomeTimeout:
+		return "timeout"
+	case OutcomeSkipped:
+		return "skipped"
+	default:
+		return "unknown"
+	}
+}
+
+// HandleReport 是单个处理函数的执行报告
+// HandleReport is the execution report of a single handle function
+type HandleReport struct {
+	// Name 是处理函数的名称
+	// Name is the name of the handle function
+	Name string
+
+	// Start 是处理函数开始执行的时间
+	// Start is the time when the handle function started
+	Start time.Time
+
+	// End 是处理函数执行完成的时间
+	// End is the time when the handle function completed
+	End time.Time
+
+	// Duration 是处理函数的执行时长
+	// Duration is the execution duration of the handle function
+	Duration time.Duration
+
+	// Outcome 是处理函数的执行结果
+	// Outcome is the result of the handle function
+	Outcome Outcome
+
+	// Err 是处理函数执行失败时记录的错误
+	// Err is the error recorded when the handle function failed
+	Err error
+}
+
+// ShutdownReport 是一次关闭过程的执行报告
+// ShutdownReport is the execution report of a shutdown
+type ShutdownReport struct {
+	// Signal 是触发关闭的信号，以编程方式触发关闭时为 nil
+	// Signal is the signal that triggered the shutdown, nil when the shutdown was triggered programmatically
+	Signal os.Signal
+
+	// Reason 是关闭的原因，由信号触发时为信号的名称
+	// Reason is the reason of the shutdown, the name of the signal when triggered by a signal
+	Reason string
+
+	// Start 是关闭开始的时间
+	// Start is the time when the shutdown started
+	Start time.Time
+
+	// End 是关闭完成的时间
+	// End is the time when the shutdown completed
+	End time.Time
+
+	// Duration 是关闭的总时长
+	// Duration is the total duration of the shutdown
+	Duration time.Duration
+
+	// Handles 是所有处理函数的执行报告
+	// Handles are the execution reports of all handle functions
+	Handles []HandleReport
+}
+
+// newShutdownReport 根据所有 TerminateSignal 中处理函数的执行情况创建关闭报告
+// newShutdownReport creates a shutdown report based on the execution of the handle functions in all TerminateSignal
+func newShutdownReport(sig os.Signal, reason string, start time.Time, sigs []*TerminateSignal) *ShutdownReport {
+	// 初始化关闭报告
+	// Initialize the shutdown report
+	end := time.Now()
+	r := &ShutdownReport{
+		Signal:   sig,
+		Reason:   reason,
+		Start:    start,
+		End:      end,
+		Duration: end.Sub(start),
+		Handles:  make([]HandleReport, 0),
+	}
+
+	// 收集所有 TerminateSignal 中处理函数的执行报告
+	// Collect the execution reports of the handle functions in all TerminateSignal
+	for _, ts := range sigs {
+		r.Handles = append(r.Handles, ts.Report()...)
+	}
+
+	// 返回关闭报告
+	// Return the shutdown report
+	return r
+}
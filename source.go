@@ -0,0 +1,114 @@
+package gs
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// SignalSource 是系统信号的来源，默认使用 os/signal，测试中可以替换为 ManualSignalSource
+// SignalSource is the source of system signals, os/signal is used by default, it can be replaced with ManualSignalSource in tests
+type SignalSource interface {
+	// Notify 使 c 开始接收指定的信号，与 signal.Notify 语义相同
+	// Notify makes c start receiving the given signals, with the same semantics as signal.Notify
+	Notify(c chan<- os.Signal, sigs ...os.Signal)
+
+	// Stop 使 c 停止接收信号，与 signal.Stop 语义相同
+	// Stop makes c stop receiving signals, with the same semantics as signal.Stop
+	Stop(c chan<- os.Signal)
+}
+
+// osSignalSource 是基于 os/signal 的默认信号来源
+// osSignalSource is the default signal source based on os/signal
+type osSignalSource struct{}
+
+// Notify 调用 signal.Notify
+// Notify calls signal.Notify
+func (osSignalSource) Notify(c chan<- os.Signal, sigs ...os.Signal) {
+	signal.Notify(c, sigs...)
+}
+
+// Stop 调用 signal.Stop
+// Stop calls signal.Stop
+func (osSignalSource) Stop(c chan<- os.Signal) {
+	signal.Stop(c)
+}
+
+// ManualSignalSource 是一个可以手动发送信号的信号来源，用于在单元测试中触发关闭，而不需要向测试进程发送真实的信号
+// ManualSignalSource is a signal source that can send signals manually, used to trigger the shutdown in unit tests without sending real signals to the test process
+type ManualSignalSource struct {
+	// mu 是一个 sync.Mutex 实例，用于保护 subs 的并发访问
+	// mu is a sync.Mutex instance, used to protect concurrent access to subs
+	mu sync.Mutex
+
+	// subs 记录了每个通道订阅的信号，空切片表示订阅所有信号
+	// subs records the signals subscribed by each channel, an empty slice means subscribing to all signals
+	subs map[chan<- os.Signal][]os.Signal
+}
+
+// NewManualSignalSource 创建一个新的 ManualSignalSource 实例
+// NewManualSignalSource creates a new ManualSignalSource instance
+func NewManualSignalSource() *ManualSignalSource {
+	return &ManualSignalSource{
+		mu:   sync.Mutex{},
+		subs: make(map[chan<- os.Signal][]os.Signal),
+	}
+}
+
+// Notify 使 c 开始接收指定的信号
+// Notify makes c start receiving the given signals
+func (m *ManualSignalSource) Notify(c chan<- os.Signal, sigs ...os.Signal) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subs[c] = append(m.subs[c], sigs...)
+}
+
+// Stop 使 c 停止接收信号
+// Stop makes c stop receiving signals
+func (m *ManualSignalSource) Stop(c chan<- os.Signal) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.subs, c)
+}
+
+// Fire 向所有订阅了 sig 的通道发送信号，与 os/signal 一样不会阻塞，返回是否至少有一个通道收到了信号
+// Fire sends sig to all channels subscribed to it, it does not block just like os/signal, returns whether at least one channel received the signal
+func (m *ManualSignalSource) Fire(sig os.Signal) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delivered := false
+	for c, sigs := range m.subs {
+		// 检查通道是否订阅了该信号
+		// Check whether the channel subscribed to the signal
+		if !containsSignal(sigs, sig) {
+			continue
+		}
+
+		// 非阻塞地发送信号，通道已满时丢弃信号
+		// Send the signal without blocking, the signal is dropped when the channel is full
+		select {
+		case c <- sig:
+			delivered = true
+		default:
+		}
+	}
+
+	// 返回是否至少有一个通道收到了信号
+	// Return whether at least one channel received the signal
+	return delivered
+}
+
+// containsSignal 判断 sigs 是否包含 sig，空切片表示包含所有信号
+// containsSignal reports whether sigs contains sig, an empty slice means containing all signals
+func containsSignal(sigs []os.Signal, sig os.Signal) bool {
+	if len(sigs) == 0 {
+		return true
+	}
+	for _, s := range sigs {
+		if s == sig {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package gs
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManualSignalSource_Fire(t *testing.T) {
+	src := NewManualSignalSource()
+	c1 := make(chan os.Signal, 1)
+	c2 := make(chan os.Signal, 1)
+	src.Notify(c1, syscall.SIGTERM)
+	src.Notify(c2)
+
+	assert.True(t, src.Fire(syscall.SIGINT))
+	assert.Equal(t, syscall.SIGINT, <-c2)
+	assert.Len(t, c1, 0)
+
+	assert.True(t, src.Fire(syscall.SIGTERM))
+	assert.Equal(t, syscall.SIGTERM, <-c1)
+	assert.Equal(t, syscall.SIGTERM, <-c2)
+
+	src.Stop(c1)
+	src.Stop(c2)
+	assert.False(t, src.Fire(syscall.SIGTERM))
+}
+
+func TestManualSignalSource_Manager(t *testing.T) {
+	src := NewManualSignalSource()
+
+	for i := 0; i < 3; i++ {
+		sig := NewTerminateSignal()
+		rec := newTestRecorder()
+		sig.Register("db", rec.handle("db"), WithPhase(2))
+		sig.Register("queue", rec.handle("queue"), WithPhase(1))
+		sig.Register("http", rec.handle("http"))
+
+		m := NewManager(WithSignalSource(src), WithTerminateSignals(sig))
+		m.Start()
+		assert.False(t, src.Fire(syscall.SIGHUP))
+		assert.True(t, src.Fire(syscall.SIGTERM))
+
+		r, err := m.Wait()
+		assert.NoError(t, err)
+		assert.Equal(t, syscall.SIGTERM, r.Signal)
+		assert.Equal(t, []string{"http", "queue", "db"}, rec.order())
+	}
+}
+
+func TestManualSignalSource_StopDuringShutdown(t *testing.T) {
+	for _, forceExit := range []bool{false, true} {
+		src := NewManualSignalSource()
+		sig := NewTerminateSignal()
+		started := make(chan struct{})
+		release := make(chan struct{})
+		sig.Register("stuck", func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+
+		opts := []Option{WithSignalSource(src), WithTerminateSignals(sig)}
+		if forceExit {
+			opts = append(opts, WithForceExit(3, 1))
+		}
+		m := NewManager(opts...)
+		m.OnReload(func() {})
+		m.Start()
+		assert.True(t, src.Fire(syscall.SIGINT))
+		<-started
+
+		assert.Equal(t, forceExit, src.Fire(syscall.SIGINT))
+		assert.Eventually(t, func() bool { return src.Fire(syscall.SIGHUP) }, time.Second, time.Millisecond)
+		m.OnSignal(syscall.SIGQUIT, func() {})
+		assert.Eventually(t, func() bool { return src.Fire(syscall.SIGQUIT) }, time.Second, time.Millisecond)
+
+		close(release)
+		_, err := m.Wait()
+		assert.NoError(t, err)
+		assert.False(t, src.Fire(syscall.SIGINT))
+		assert.False(t, src.Fire(syscall.SIGHUP))
+	}
+}
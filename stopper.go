@@ -0,0 +1,64 @@
+package gs
+
+import "context"
+
+// grpcServerName 是 RegisterGracefulStopper 注册的处理函数的默认名称
+// grpcServerName is the default name of the handle function registered by RegisterGracefulStopper
+const grpcServerName = "grpc-server"
+
+// GracefulStopper 接口描述了可以优雅停止和强制停止的服务，例如 *grpc.Server，gs 不依赖 gRPC
+// The GracefulStopper interface describes a server that can be stopped gracefully and forcibly, e.g. *grpc.Server, gs does not depend on gRPC
+type GracefulStopper interface {
+	// GracefulStop 停止接收新的连接和请求，并阻塞直到在途请求全部完成
+	// GracefulStop stops accepting new connections and requests, and blocks until all in-flight requests have completed
+	GracefulStop()
+
+	// Stop 立即关闭所有的连接和请求
+	// Stop immediately closes all connections and requests
+	Stop()
+}
+
+// RegisterGracefulStopper 将 GracefulStopper 注册到 TerminateSignal，关闭时先调用 GracefulStop，
+// 截止时间到达后调用 Stop 强制停止，并将截止时间的错误记录在关闭报告和汇总错误中
+// 处理函数的默认名称为 "grpc-server"，可以使用 WithName 修改，opts 同样可以设置关闭阶段、依赖和超时时间
+// RegisterGracefulStopper registers the GracefulStopper to the TerminateSignal, on close GracefulStop is called first,
+// after the deadline Stop is called to stop forcibly, and the deadline error is recorded in the shutdown report and the aggregated error
+// The default name of the handle function is "grpc-server", which can be changed with WithName, opts can also set the shutdown phase, dependencies and timeout
+func RegisterGracefulStopper(sig *TerminateSignal, srv GracefulStopper, opts ...HandleOption) (unregister func() bool, err error) {
+	// 如果没有提供 GracefulStopper，那么直接返回
+	// If no GracefulStopper is provided, then return directly
+	if sig == nil || srv == nil {
+		return func() bool { return false }, nil
+	}
+
+	// 以默认名称注册，opts 中的 WithName 会覆盖默认名称
+	// Register with the default name, WithName in opts overrides the default name
+	return sig.Register(grpcServerName, stopGracefulStopper(srv), opts...)
+}
+
+// stopGracefulStopper 返回停止 GracefulStopper 的处理函数，ctx 结束之前 GracefulStop 没有返回时调用 Stop
+// stopGracefulStopper returns the handle function that stops the GracefulStopper, Stop is called when GracefulStop has not returned before ctx is done
+func stopGracefulStopper(srv GracefulStopper) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		// 在新的 goroutine 中优雅停止，完成后关闭 done 通道
+		// Stop gracefully in a new goroutine, and close the done channel when finished
+		done := make(chan struct{})
+		go func() {
+			srv.GracefulStop()
+			close(done)
+		}()
+
+		// 等待优雅停止完成或者截止时间到达
+		// Wait for the graceful stop to complete or the deadline to be reached
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+		}
+
+		// 截止时间到达，强制停止，返回截止时间的错误
+		// The deadline is reached, stop forcibly and return the deadline error
+		srv.Stop()
+		return ctx.Err()
+	}
+}
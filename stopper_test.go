@@ -0,0 +1,100 @@
+package gs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeGRPCServer struct {
+	mu       sync.Mutex
+	inflight sync.WaitGroup
+	stopped  bool
+	force    chan struct{}
+}
+
+func newFakeGRPCServer(inflight int) *fakeGRPCServer {
+	s := &fakeGRPCServer{force: make(chan struct{})}
+	s.inflight.Add(inflight)
+	return s
+}
+
+func (s *fakeGRPCServer) finish() { s.inflight.Done() }
+
+func (s *fakeGRPCServer) GracefulStop() {
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-s.force:
+	}
+}
+
+func (s *fakeGRPCServer) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.force)
+	}
+}
+
+func (s *fakeGRPCServer) isStopped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stopped
+}
+
+func TestRegisterGracefulStopper(t *testing.T) {
+	srv := newFakeGRPCServer(1)
+	sig := NewTerminateSignal()
+	_, err := RegisterGracefulStopper(sig, srv)
+	assert.NoError(t, err)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		srv.finish()
+	}()
+
+	assert.NoError(t, sig.Close(nil))
+	assert.False(t, srv.isStopped())
+	assert.Equal(t, grpcServerName, sig.Report()[0].Name)
+	assert.Equal(t, OutcomeOK, sig.Report()[0].Outcome)
+}
+
+func TestRegisterGracefulStopper_Deadline(t *testing.T) {
+	srv := newFakeGRPCServer(1)
+	defer srv.finish()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := stopGracefulStopper(srv)(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, srv.isStopped())
+}
+
+func TestRegisterGracefulStopper_Timeout(t *testing.T) {
+	srv := newFakeGRPCServer(1)
+	defer srv.finish()
+
+	sig := NewTerminateSignal()
+	_, err := RegisterGracefulStopper(sig, srv)
+	assert.NoError(t, err)
+
+	m := NewManager(WithSignalSource(NewManualSignalSource()), WithTerminateSignals(sig), WithTimeout(200*time.Millisecond), WithTimeoutGrace(time.Second))
+	m.Shutdown("test")
+	r, err := m.Wait()
+
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, srv.isStopped())
+	assert.Equal(t, grpcServerName, r.Handles[0].Name)
+	assert.Equal(t, OutcomeError, r.Handles[0].Outcome)
+	assert.ErrorIs(t, r.Handles[0].Err, context.DeadlineExceeded)
+}
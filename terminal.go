@@ -5,6 +5,7 @@ import (
 	"errors"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // TerminateSignal 结构体包含了一个 context，一个取消函数，一个等待组，一个函数切片和一个 sync.Once 实例
@@ -22,9 +23,13 @@ type TerminateSignal struct {
 	// wg is a sync.WaitGroup instance, used to wait for all goroutines to complete
 	wg sync.WaitGroup
 
-	// handles 是一个函数切片，包含了所有需要在终止信号发生时执行的处理函数
-	// handles is a function slice, containing all handle functions that need to be executed when the termination signal occurs
-	handles []func()
+	// mu 是一个 sync.Mutex 实例，用于保护 handles 和 closed 的并发访问
+	// mu is a sync.Mutex instance, used to protect concurrent access to handles and closed
+	mu sync.Mutex
+
+	// handles 是一个 handle 切片，包含了所有需要在终止信号发生时执行的处理函数
+	// handles is a handle slice, containing all handle functions that need to be executed when the termination signal occurs
+	handles []*handle
 
 	// once 是一个 sync.Once 实例，用于确保某个操作只执行一次
 	// once is a sync.Once instance, used to ensure that an operation is only performed once
@@ -33,6 +38,26 @@ type TerminateSignal struct {
 	// closed 是一个 atomic.Bool 实例，用于标记 TerminateSignal 是否已经关闭
 	// closed is an atomic.Bool instance, used to mark whether the TerminateSignal is closed
 	closed atomic.Bool
+
+	// done 是所有处理函数执行完成后关闭的通道
+	// done is the channel closed after all handle functions have completed
+	done chan struct{}
+
+	// workers 是一个 sync.WaitGroup 实例，用于等待所有通过 Go 启动的后台 goroutine 完成
+	// workers is a sync.WaitGroup instance, used to wait for all background goroutines started by Go to complete
+	workers sync.WaitGroup
+
+	// workerErrs 是后台 goroutine 返回的错误，受 mu 保护
+	// workerErrs are the errors returned by the background goroutines, protected by mu
+	workerErrs []error
+
+	// planErrs 是关闭计划的错误，例如循环依赖和未知名称，受 mu 保护
+	// planErrs are the errors of the shutdown plan, such as dependency cycles and unknown names, protected by mu
+	planErrs []error
+
+	// errs 是关闭完成后的所有错误，包括关闭计划的错误、后台 goroutine 的错误和处理函数的错误
+	// errs are all errors after the close is completed, including the errors of the shutdown plan, the background goroutines and the handle functions
+	errs []error
 }
 
 // NewTerminateSignalWithContext 创建一个带有上下文和超时的 TerminateSignal 实例
@@ -45,9 +70,13 @@ func NewTerminateSignalWithContext(ctx context.Context) *TerminateSignal {
 		// wg is a sync.WaitGroup instance, used to wait for all goroutines to complete
 		wg: sync.WaitGroup{},
 
-		// handles 是一个函数切片，包含了所有需要在终止信号发生时执行的处理函数
-		// handles is a function slice, containing all handle functions that need to be executed when the termination signal occurs
-		handles: make([]func(), 0),
+		// mu 是一个 sync.Mutex 实例，用于保护 handles 和 closed 的并发访问
+		// mu is a sync.Mutex instance, used to protect concurrent access to handles and closed
+		mu: sync.Mutex{},
+
+		// handles 是一个 handle 切片，包含了所有需要在终止信号发生时执行的处理函数
+		// handles is a handle slice, containing all handle functions that need to be executed when the termination signal occurs
+		handles: make([]*handle, 0),
 
 		// once 是一个 sync.Once 实例，用于确保某个操作只执行一次
 		// once is a sync.Once instance, used to ensure that an operation is only performed once
@@ -56,6 +85,10 @@ func NewTerminateSignalWithContext(ctx context.Context) *TerminateSignal {
 		// closed 是一个 atomic.Bool 实例，用于标记 TerminateSignal 是否已经关闭
 		// closed is an atomic.Bool instance, used to mark whether the TerminateSignal is closed
 		closed: atomic.Bool{},
+
+		// done 是所有处理函数执行完成后关闭的通道
+		// done is the channel closed after all handle functions have completed
+		done: make(chan struct{}),
 	}
 
 	// 将 closed 的值设置为 false，表示 TerminateSignal 还没有关闭
@@ -79,64 +112,369 @@ func NewTerminateSignal() *TerminateSignal {
 	return NewTerminateSignalWithContext(context.Background())
 }
 
-// RegisterCancelHandles 注册需要取消的处理函数
-// RegisterCancelHandles registers the handle functions to be canceled
-func (s *TerminateSignal) RegisterCancelHandles(handles ...func()) {
-	// 如果 TerminateSignal 已经关闭，那么直接返回
-	// If the TerminateSignal is already closed, then return directly
+// register 在锁的保护下将处理函数添加到 s.handles 切片中，如果 TerminateSignal 已经关闭，那么返回 ErrAlreadyClosed
+// register adds the handle functions to the s.handles slice under the protection of the lock, if the TerminateSignal is already closed, then return ErrAlreadyClosed
+func (s *TerminateSignal) register(handles ...*handle) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 如果 TerminateSignal 已经关闭，那么返回 ErrAlreadyClosed
+	// If the TerminateSignal is already closed, then return ErrAlreadyClosed
 	if s.closed.Load() {
-		return
+		return ErrAlreadyClosed
 	}
 
-	// 将回调函数添加到 s.exec 切片中
-	// Add the callback functions to the s.exec slice
+	// 将处理函数添加到 s.handles 切片中
+	// Add the handle functions to the s.handles slice
 	s.handles = append(s.handles, handles...)
+	return nil
 }
 
-// GetStopContext 获取停止信号的 Context
-// GetStopContext gets the Context of the stop signal
+// RegisterCancelHandles 注册需要取消的处理函数，如果 TerminateSignal 已经关闭，那么返回 ErrAlreadyClosed
+// RegisterCancelHandles registers the handle functions to be canceled, if the TerminateSignal is already closed, then return ErrAlreadyClosed
+func (s *TerminateSignal) RegisterCancelHandles(handles ...func()) error {
+	// 将非空的回调函数包装成 handle
+	// Wrap the non-nil callback functions into handles
+	hs := make([]*handle, 0, len(handles))
+	for _, fn := range handles {
+		if fn != nil {
+			hs = append(hs, newHandle(funcName(fn), wrapHandle(fn)))
+		}
+	}
+
+	// 注册所有的 handle
+	// Register all handles
+	return s.register(hs...)
+}
+
+// Register 注册一个带有名称的处理函数，名称会出现在关闭报告和错误信息中，opts 用于设置关闭阶段等注册参数
+// 返回的 unregister 函数用于在资源正常结束时移除该处理函数，与 context.AfterFunc 类似，如果在关闭开始之前成功移除，那么返回 true
+// 如果 TerminateSignal 已经关闭，那么返回 ErrAlreadyClosed，此时 unregister 函数总是返回 false
+// Register registers a named handle function, the name appears in the shutdown report and error messages, opts are used to set registration parameters such as the shutdown phase
+// The returned unregister function removes the handle function when the resource finishes normally, similar to context.AfterFunc, it returns true if the handle function was removed before the close started
+// If the TerminateSignal is already closed, then return ErrAlreadyClosed, in which case the unregister function always returns false
+func (s *TerminateSignal) Register(name string, fn func(ctx context.Context) error, opts ...HandleOption) (unregister func() bool, err error) {
+	// 如果回调函数为空，那么直接返回
+	// If the callback function is nil, then return directly
+	if fn == nil {
+		return func() bool { return false }, nil
+	}
+
+	// 将回调函数包装成带有名称的 handle 并注册
+	// Wrap the callback function into a named handle and register it
+	h := newHandle(name, fn, opts...)
+	if err := s.register(h); err != nil {
+		return func() bool { return false }, err
+	}
+
+	// 返回用于移除该处理函数的函数
+	// Return the function used to remove the handle function
+	return func() bool { return s.unregister(h) }, nil
+}
+
+// unregister 在锁的保护下从 s.handles 切片中移除处理函数，如果 TerminateSignal 已经关闭或者处理函数已经被移除，那么返回 false
+// unregister removes the handle function from the s.handles slice under the protection of the lock, returns false if the TerminateSignal is already closed or the handle function has already been removed
+func (s *TerminateSignal) unregister(h *handle) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 如果 TerminateSignal 已经关闭，那么处理函数已经或者即将执行，不能再移除
+	// If the TerminateSignal is already closed, then the handle function has been or is about to be executed and can no longer be removed
+	if s.closed.Load() {
+		return false
+	}
+
+	// 查找并移除处理函数，移除时保持其余处理函数的注册顺序
+	// Find and remove the handle function, keeping the registration order of the remaining handle functions
+	for i, v := range s.handles {
+		if v == h {
+			copy(s.handles[i:], s.handles[i+1:])
+			s.handles[len(s.handles)-1] = nil
+			s.handles = s.handles[:len(s.handles)-1]
+			return true
+		}
+	}
+
+	// 处理函数已经被移除
+	// The handle function has already been removed
+	return false
+}
+
+// RegisterShutdownHandles 注册带有 context 和错误返回值的处理函数，ctx 携带了剩余的截止时间，返回的错误会在关闭完成后汇总
+// 如果 TerminateSignal 已经关闭，那么返回 ErrAlreadyClosed
+// RegisterShutdownHandles registers handle functions with context and error return value, ctx carries the remaining deadline, the returned errors are aggregated after the close is completed
+// If the TerminateSignal is already closed, then return ErrAlreadyClosed
+func (s *TerminateSignal) RegisterShutdownHandles(handles ...func(ctx context.Context) error) error {
+	// 将非空的回调函数包装成 handle
+	// Wrap the non-nil callback functions into handles
+	hs := make([]*handle, 0, len(handles))
+	for _, fn := range handles {
+		if fn != nil {
+			hs = append(hs, newHandle(funcName(fn), fn))
+		}
+	}
+
+	// 注册所有的 handle
+	// Register all handles
+	return s.register(hs...)
+}
+
+// RegisterCancelHandleWithTimeout 注册一个带有超时时间的处理函数，超时后不再等待该处理函数并将其记录为超时
+// 如果 TerminateSignal 已经关闭，那么返回 ErrAlreadyClosed
+// RegisterCancelHandleWithTimeout registers a handle function with a timeout, after the timeout the handle function is no longer waited for and is recorded as timed out
+// If the TerminateSignal is already closed, then return ErrAlreadyClosed
+func (s *TerminateSignal) RegisterCancelHandleWithTimeout(fn func(), timeout time.Duration) error {
+	// 如果回调函数为空，那么直接返回
+	// If the callback function is nil, then return directly
+	if fn == nil {
+		return nil
+	}
+
+	// 将回调函数包装成带有超时时间的 handle 并注册
+	// Wrap the callback function into a handle with a timeout and register it
+	return s.register(newHandle(funcName(fn), wrapHandle(fn), WithHandleTimeout(timeout)))
+}
+
+// Go 启动一个与停止信号的 context 绑定的后台 goroutine，关闭开始时 ctx 被取消，关闭会等待所有的后台 goroutine 返回之后才执行处理函数
+// fn 返回的错误（context.Canceled 除外）会在关闭完成后汇总，fn 发生 panic 时记录为 PanicError，如果 TerminateSignal 已经关闭，那么返回 ErrAlreadyClosed
+// Go starts a background goroutine bound to the context of the stop signal, ctx is cancelled when the close begins, and the close waits for all background goroutines to return before running the handle functions
+// The error returned by fn (except context.Canceled) is aggregated after the close is completed, a panic in fn is recorded as a PanicError, if the TerminateSignal is already closed, then return ErrAlreadyClosed
+func (s *TerminateSignal) Go(fn func(ctx context.Context) error) error {
+	// 如果回调函数为空，那么直接返回
+	// If the callback function is nil, then return directly
+	if fn == nil {
+		return nil
+	}
+
+	// 在锁的保护下增加后台 goroutine 的计数，保证关闭开始之后不会再有新的后台 goroutine
+	// Increase the count of background goroutines under the protection of the lock, so that no new background goroutine starts after the close begins
+	s.mu.Lock()
+	if s.closed.Load() {
+		s.mu.Unlock()
+		return ErrAlreadyClosed
+	}
+	s.workers.Add(1)
+	s.mu.Unlock()
+
+	// 在新的 goroutine 中执行回调函数，与处理函数一样在 recover 中执行
+	// Execute the callback function in a new goroutine, executed under recover just like the handle functions
+	h := newHandle(funcName(fn), fn)
+	go func() {
+		defer s.workers.Done()
+		if err := h.run(s.ctx); err != nil && !errors.Is(err, context.Canceled) {
+			s.mu.Lock()
+			s.workerErrs = append(s.workerErrs, &HandleError{Name: h.name, Err: err})
+			s.mu.Unlock()
+		}
+	}()
+
+	return nil
+}
+
+// waitWorkers 等待所有的后台 goroutine 返回，ctx 结束时立即返回
+// waitWorkers waits for all background goroutines to return, it returns immediately when ctx is done
+func (s *TerminateSignal) waitWorkers(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// snapshot 在锁的保护下返回当前所有处理函数的副本
+// snapshot returns a copy of all current handle functions under the protection of the lock
+func (s *TerminateSignal) snapshot() []*handle {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	handles := make([]*handle, len(s.handles))
+	copy(handles, s.handles)
+	return handles
+}
+
+// GetStopContext 获取停止信号的 Context，关闭一开始它就会被取消
+// GetStopContext gets the Context of the stop signal, it is cancelled as soon as the close begins
 func (s *TerminateSignal) GetStopContext() context.Context {
 	// 返回 s.ctx，即停止信号的 Context
 	// Return s.ctx, which is the Context of the stop signal
 	return s.ctx
 }
 
-// worker 是一个执行回调函数的方法
-// worker is a method that executes the callback function
-func (s *TerminateSignal) worker(fn func()) {
+// Done 返回一个通道，所有处理函数执行完成后该通道会被关闭
+// Done returns a channel that is closed after all handle functions have completed
+func (s *TerminateSignal) Done() <-chan struct{} {
+	return s.done
+}
+
+// pending 返回所有尚未执行完成的处理函数名称
+// pending returns the names of all handle functions that have not completed yet
+func (s *TerminateSignal) pending() []string {
+	names := make([]string, 0)
+	for _, h := range s.snapshot() {
+		if h.report().Outcome == OutcomePending {
+			names = append(names, h.name)
+		}
+	}
+	return names
+}
+
+// pendingAt 返回在 t 时刻已经开始但尚未执行完成的处理函数名称，包括此后才完成的处理函数，尚未开始和被跳过的处理函数除外
+// pendingAt returns the names of the handle functions that had started but not completed at time t, including those completed afterwards, except the handle functions not started yet and the skipped ones
+func (s *TerminateSignal) pendingAt(t time.Time) []string {
+	names := make([]string, 0)
+	for _, h := range s.snapshot() {
+		r := h.report()
+		if r.Outcome == OutcomeSkipped || r.Start.IsZero() || r.Start.After(t) {
+			continue
+		}
+		if r.Outcome == OutcomePending || r.End.After(t) {
+			names = append(names, h.name)
+		}
+	}
+	return names
+}
+
+// handleErrors 返回所有处理函数执行失败时记录的错误，关闭过程中调用时只包含已经完成的处理函数
+// handleErrors returns the errors recorded by all failed handle functions, when called during the close only the completed handle functions are included
+func (s *TerminateSignal) handleErrors() []error {
+	errs := make([]error, 0)
+	for _, h := range s.snapshot() {
+		if err := h.report().Err; err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// closeErrors 返回关闭计划、后台 goroutine 和所有处理函数已经记录的错误，关闭过程中也可以调用
+// closeErrors returns the errors already recorded by the shutdown plan, the background goroutines and all handle functions, it can also be called during the close
+func (s *TerminateSignal) closeErrors() []error {
+	s.mu.Lock()
+	errs := make([]error, 0, len(s.planErrs)+len(s.workerErrs))
+	errs = append(errs, s.planErrs...)
+	errs = append(errs, s.workerErrs...)
+	s.mu.Unlock()
+	return append(errs, s.handleErrors()...)
+}
+
+// Report 返回所有处理函数的执行报告，关闭过程中调用时，未完成的处理函数的执行结果为 OutcomePending
+// Report returns the execution reports of all handle functions, when called during the close, the result of unfinished handle functions is OutcomePending
+func (s *TerminateSignal) Report() []HandleReport {
+	handles := s.snapshot()
+	reports := make([]HandleReport, 0, len(handles))
+	for _, h := range handles {
+		reports = append(reports, h.report())
+	}
+	return reports
+}
+
+// worker 是一个执行回调函数的方法，ctx 携带了剩余的截止时间
+// worker is a method that executes the callback function, ctx carries the remaining deadline
+func (s *TerminateSignal) worker(ctx context.Context, h *handle, obs Observer) {
 	// 在函数返回时，调用 Done 方法
 	// Call the Done method when the function returns
 	defer s.wg.Done()
 
-	// 如果 s.ctx 已经超时的话，那么直接返回
-	// If s.ctx has already timed out, then return directly
-	if err := s.ctx.Err(); err != nil {
-		if !errors.Is(err, context.Canceled) {
-			return
-		}
+	// 如果关闭的截止时间已经到达或者 s.ctx 已经超时的话，那么记录处理函数被跳过并直接返回，不再启动新的处理函数
+	// If the deadline of the close has been reached or s.ctx has already timed out, then record that the handle function was skipped and return directly, no new handle function is started
+	if ctx.Err() != nil || errors.Is(s.ctx.Err(), context.DeadlineExceeded) {
+		h.skip()
+		return
+	}
+
+	// 通知观察者并记录处理函数开始执行的时间，返回时通知观察者处理函数的执行结果
+	// Notify the observer and record the time when the handle function started, notify the observer of the result of the handle function when returning
+	obs.OnHandleStart(h.name)
+	h.begin()
+	defer func() {
+		r := h.report()
+		obs.OnHandleDone(r.Name, r.Duration, r.Err)
+	}()
+
+	// 如果没有设置超时时间，那么直接执行注册待执行的函数并记录结果
+	// If no timeout is set, then execute the registered function directly and record the result
+	if h.timeout <= 0 {
+		h.finish(h.run(ctx))
+		return
 	}
 
-	// 执行注册待执行的函数
-	// Execute the registered function
-	fn()
+	// 创建一个带有处理函数超时时间的 context
+	// Create a context with the timeout of the handle function
+	hctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	// 在新的 goroutine 中执行注册待执行的函数，完成后将结果发送到 result 通道
+	// Execute the registered function in a new goroutine, and send the result to the result channel when finished
+	result := make(chan error, 1)
+	go func() {
+		result <- h.run(hctx)
+	}()
+
+	// 等待处理函数完成或者超时，超时后不再等待并记录超时错误，
+	// 如果是关闭的截止时间先到达，那么记录截止时间的错误，以便与处理函数自己的超时区分
+	// Wait for the handle function to complete or time out, after the timeout stop waiting and record the timeout error,
+	// if the deadline of the close is reached first, then record the deadline error, so that it can be told apart from the handle function's own timeout
+	select {
+	case err := <-result:
+		h.finish(err)
+	case <-hctx.Done():
+		if err := ctx.Err(); err != nil {
+			h.finish(err)
+		} else {
+			h.finish(ErrHandleTimeout)
+		}
+	}
 }
 
-// close 关闭 TerminateSignal 实例
-// close the TerminateSignal instance
-func (s *TerminateSignal) close(closeMode CloseType, wg *sync.WaitGroup) {
+// close 关闭 TerminateSignal 实例，ctx 携带了关闭的截止时间，返回所有处理函数错误的集合
+// close the TerminateSignal instance, ctx carries the deadline of the close, returns the collection of all handle function errors
+func (s *TerminateSignal) close(ctx context.Context, closeMode CloseType, obs Observer, wg *sync.WaitGroup) error {
 	// 使用 sync.Once 确保 Close 只被执行一次
 	// Use sync.Once to ensure Close is only executed once
 	s.once.Do(func() {
-		// 将 closed 的值设置为 true，表示 TerminateSignal 已经关闭
-		// Set the value of closed to true, indicating that the TerminateSignal is closed
+		// 在锁的保护下将 closed 的值设置为 true，表示 TerminateSignal 已经关闭，之后的注册都会返回 ErrAlreadyClosed
+		// Set the value of closed to true under the protection of the lock, indicating that the TerminateSignal is closed, all later registrations return ErrAlreadyClosed
+		s.mu.Lock()
 		s.closed.Store(true)
+		handles := s.handles
+		s.mu.Unlock()
+
+		// 关闭一开始就取消停止信号的 context，让监听它的 goroutine 在处理函数执行之前停止接收新的工作
+		// Cancel the context of the stop signal as soon as the close begins, so that goroutines watching it stop accepting new work before the handle functions run
+		s.cancel()
+
+		// 在执行任何处理函数之前，等待所有的后台 goroutine 返回，截止时间到达时不再等待
+		// Wait for all background goroutines to return before any handle function runs, stop waiting when the deadline is reached
+		s.waitWorkers(ctx)
+
+		// 根据阶段和依赖关系生成关闭计划
+		// Generate the shutdown plan according to the phases and dependencies
+		p := newPlan(handles)
+		s.mu.Lock()
+		s.planErrs = p.errs
+		s.mu.Unlock()
+
+		// 按关闭阶段依次遍历所有的回调函数
+		// Iterate over all callback functions phase by phase
+		for i, phase := range p.phases {
+			// 在进入下一个阶段之前，等待上一个阶段的所有 worker 完成
+			// Before entering the next phase, wait for all workers of the previous phase to complete
+			if i > 0 {
+				s.wg.Wait()
+			}
+
+			// done 记录同一阶段内每个处理函数完成时关闭的通道，用于等待前置处理函数
+			// done records the channel closed when each handle function in the same phase completes, used to wait for predecessors
+			done := make(map[*handle]chan struct{}, len(phase))
+			for _, h := range phase {
+				done[h] = make(chan struct{})
+			}
 
-		// 遍历所有的回调函数
-		// Iterate over all callback functions
-		for _, fn := range s.handles {
-			// 如果回调函数不为空
-			// If the callback function is not null
-			if fn != nil {
+			for _, h := range phase {
 				// 增加等待组的计数，表示有一个新的任务需要等待完成
 				// Increase the count of the wait group, indicating that there is a new task to wait for completion
 				s.wg.Add(1)
@@ -147,48 +485,62 @@ func (s *TerminateSignal) close(closeMode CloseType, wg *sync.WaitGroup) {
 				// ASyncClose 表示异步关闭
 				// ASyncClose indicates asynchronous close
 				case ASyncClose:
-					// 在新的 goroutine 中执行 worker 函数，这样可以并发执行多个任务
-					// Execute the worker function in a new goroutine, so that multiple tasks can be executed concurrently
-					go s.worker(fn)
+					// 在新的 goroutine 中等待所有前置处理函数完成后执行 worker 函数，这样可以并发执行同一阶段中相互独立的多个任务
+					// Execute the worker function in a new goroutine after all predecessors complete, so that independent tasks in the same phase can be executed concurrently
+					go func(h *handle) {
+						defer close(done[h])
+						for _, pred := range p.preds[h] {
+							<-done[pred]
+						}
+						s.worker(ctx, h, obs)
+					}(h)
 
 				// SyncClose 表示同步关闭
 				// SyncClose indicates synchronous close
 				case SyncClose:
-					// 在当前 goroutine 中执行 worker 函数，这样可以保证任务按顺序执行
-					// Execute the worker function in the current goroutine, so that tasks can be executed in order
-					s.worker(fn)
+					// 在当前 goroutine 中按拓扑顺序执行 worker 函数，这样可以保证任务按顺序执行
+					// Execute the worker function in the current goroutine in topological order, so that tasks can be executed in order
+					s.worker(ctx, h, obs)
 				}
 			}
 		}
 
-		// 取消 context
-		// Cancel the context
-		s.cancel()
-
 		// 等待所有的 worker 完成
 		// Wait for all workers to complete
 		s.wg.Wait()
 
+		// 汇总关闭计划、后台 goroutine 和所有处理函数的错误
+		// Aggregate the errors of the shutdown plan, the background goroutines and all handle functions
+		s.errs = s.closeErrors()
+
+		// 所有处理函数执行完成，关闭 done 通道
+		// All handle functions have completed, close the done channel
+		close(s.done)
+
 		// 如果外部的等待组不为空，调用 Done 方法
 		// If the external wait group is not null, call the Done method
 		if wg != nil {
 			wg.Done()
 		}
 	})
+
+	// 返回所有错误的集合
+	// Return the collection of all errors
+	return joinErrors(s.errs...)
 }
 
-// Close 方法异步关闭 TerminateSignal 实例
-// The Close method asynchronously closes the TerminateSignal instance
-func (s *TerminateSignal) Close(wg *sync.WaitGroup) {
+// Close 方法异步关闭 TerminateSignal 实例，并返回所有处理函数错误的集合
+// The Close method asynchronously closes the TerminateSignal instance and returns the collection of all handle function errors
+func (s *TerminateSignal) Close(wg *sync.WaitGroup) error {
 	// 调用 close 方法，传入 ASyncClose 作为关闭模式和 wg 作为等待组
 	// Call the close method, passing in ASyncClose as the close mode and wg as the wait group
-	s.close(ASyncClose, wg)
+	return s.close(context.Background(), ASyncClose, NopObserver{}, wg)
 }
 
-// SyncClose 方法同步关闭 TerminateSignal 实例
-// The SyncClose method synchronously closes the TerminateSignal instance
-func (s *TerminateSignal) SyncClose(wg *sync.WaitGroup) {
+// SyncClose 方法同步关闭 TerminateSignal 实例，并返回所有处理函数错误的集合
+// The SyncClose method synchronously closes the TerminateSignal instance and returns the collection of all handle function errors
+func (s *TerminateSignal) SyncClose(wg *sync.WaitGroup) error {
 	// 调用 close 方法，传入 SyncClose 作为关闭模式和 wg 作为等待组
 	// Call the close method, passing in SyncClose as the close mode and wg as the wait group
-	s.close(SyncClose, wg)
+	return s.close(context.Background(), SyncClose, NopObserver{}, wg)
 }
@@ -2,9 +2,11 @@ package gs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -21,6 +23,41 @@ func NewTestTerminateSignal(name string) *TestTerminateSignal {
 	return &TestTerminateSignal{name: name}
 }
 
+type testRecorder struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func newTestRecorder() *testRecorder {
+	return &testRecorder{names: make([]string, 0)}
+}
+
+func (r *testRecorder) add(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.names = append(r.names, name)
+}
+
+func (r *testRecorder) handle(name string) func(ctx context.Context) error {
+	return r.handleAfter(name, 0)
+}
+
+func (r *testRecorder) handleAfter(name string, d time.Duration) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		time.Sleep(d)
+		r.add(name)
+		return nil
+	}
+}
+
+func (r *testRecorder) order() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, len(r.names))
+	copy(names, r.names)
+	return names
+}
+
 func TestTerminateSignal_Standard(t *testing.T) {
 	sig := NewTerminateSignal()
 	assert.NotNil(t, sig, "signal is nil")
@@ -65,3 +102,273 @@ func TestTerminateSignal_MultiRegisters_Sync(t *testing.T) {
 	}
 	sig.SyncClose(nil)
 }
+
+func TestTerminateSignal_HandleWithTimeout(t *testing.T) {
+	sig := NewTerminateSignal()
+	assert.NotNil(t, sig, "signal is nil")
+	block := make(chan struct{})
+	defer close(block)
+	tts := NewTestTerminateSignal("test")
+	sig.RegisterCancelHandles(tts.Close)
+	sig.RegisterCancelHandleWithTimeout(func() { <-block }, 100*time.Millisecond)
+	sig.RegisterCancelHandleWithTimeout(tts.Close, time.Second)
+
+	start := time.Now()
+	err := sig.Close(nil)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.True(t, errors.Is(err, ErrHandleTimeout))
+
+	errs := sig.handleErrors()
+	assert.Len(t, errs, 1)
+}
+
+func TestTerminateSignal_HandleWithTimeout_Deadline(t *testing.T) {
+	sig := NewTerminateSignal()
+	assert.NotNil(t, sig, "signal is nil")
+	block := make(chan struct{})
+	defer close(block)
+	sig.RegisterCancelHandleWithTimeout(func() { <-block }, time.Second)
+	sig.RegisterCancelHandleWithTimeout(func() { <-block }, 50*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	err := sig.close(ctx, ASyncClose, NopObserver{}, nil)
+	assert.ErrorIs(t, err, ErrHandleTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	reports := sig.Report()
+	assert.Equal(t, OutcomeError, reports[0].Outcome)
+	assert.ErrorIs(t, reports[0].Err, context.DeadlineExceeded)
+	assert.NotErrorIs(t, reports[0].Err, ErrHandleTimeout)
+	assert.Equal(t, OutcomeTimeout, reports[1].Outcome)
+}
+
+func TestTerminateSignal_ShutdownHandles(t *testing.T) {
+	sig := NewTerminateSignal()
+	assert.NotNil(t, sig, "signal is nil")
+	errFlush := errors.New("flush failed")
+	tts := NewTestTerminateSignal("test")
+	sig.RegisterCancelHandles(tts.Close)
+	sig.RegisterShutdownHandles(
+		func(ctx context.Context) error { return nil },
+		func(ctx context.Context) error { return errFlush },
+		func(ctx context.Context) error {
+			_, ok := ctx.Deadline()
+			assert.False(t, ok)
+			return nil
+		},
+	)
+
+	err := sig.SyncClose(nil)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, errFlush))
+
+	var he *HandleError
+	assert.True(t, errors.As(err, &he))
+	assert.Contains(t, he.Name, "TestTerminateSignal_ShutdownHandles")
+
+	var se *ShutdownError
+	assert.True(t, errors.As(err, &se))
+	assert.Len(t, se.Errors, 1)
+
+	assert.Equal(t, err, sig.Close(nil))
+}
+
+func TestTerminateSignal_HandlePanic(t *testing.T) {
+	sig := NewTerminateSignal()
+	assert.NotNil(t, sig, "signal is nil")
+	count := 0
+	mu := sync.Mutex{}
+	for i := 0; i < 10; i++ {
+		sig.RegisterCancelHandles(func() {
+			mu.Lock()
+			count++
+			mu.Unlock()
+		})
+	}
+	sig.RegisterCancelHandles(func() { panic("boom") })
+	sig.RegisterCancelHandleWithTimeout(func() { panic("boom with timeout") }, time.Second)
+
+	err := sig.Close(nil)
+	assert.Equal(t, 10, count)
+	assert.True(t, errors.Is(err, ErrHandlePanic))
+
+	var se *ShutdownError
+	assert.True(t, errors.As(err, &se))
+	assert.Len(t, se.Errors, 2)
+
+	var pe *PanicError
+	assert.True(t, errors.As(err, &pe))
+	assert.NotEmpty(t, pe.Stack)
+}
+
+func TestTerminateSignal_Register(t *testing.T) {
+	sig := NewTerminateSignal()
+	assert.NotNil(t, sig, "signal is nil")
+	block := make(chan struct{})
+	defer close(block)
+	sig.Register("postgres", func(ctx context.Context) error { return nil })
+	sig.Register("kafka", func(ctx context.Context) error { return errors.New("flush failed") })
+	sig.Register("redis", func(ctx context.Context) error { panic("boom") })
+	sig.RegisterCancelHandleWithTimeout(func() { <-block }, 100*time.Millisecond)
+
+	for _, r := range sig.Report() {
+		assert.Equal(t, OutcomePending, r.Outcome)
+	}
+
+	err := sig.Close(nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "kafka: flush failed")
+
+	reports := sig.Report()
+	assert.Len(t, reports, 4)
+	assert.Equal(t, "postgres", reports[0].Name)
+	assert.Equal(t, OutcomeOK, reports[0].Outcome)
+	assert.NoError(t, reports[0].Err)
+	assert.Equal(t, "kafka", reports[1].Name)
+	assert.Equal(t, OutcomeError, reports[1].Outcome)
+	assert.Equal(t, "redis", reports[2].Name)
+	assert.Equal(t, OutcomePanic, reports[2].Outcome)
+	assert.Equal(t, OutcomeTimeout, reports[3].Outcome)
+	assert.GreaterOrEqual(t, reports[3].Duration, 100*time.Millisecond)
+	assert.Equal(t, reports[3].End.Sub(reports[3].Start), reports[3].Duration)
+}
+
+func TestTerminateSignal_Phases(t *testing.T) {
+	sig := NewTerminateSignal()
+	assert.NotNil(t, sig, "signal is nil")
+	rec := newTestRecorder()
+	sig.Register("db", rec.handle("db"), WithPhase(3))
+	sig.Register("drain-1", rec.handleAfter("drain", 100*time.Millisecond), WithPhase(1))
+	sig.Register("listener", rec.handleAfter("listener", 100*time.Millisecond))
+	sig.Register("drain-2", rec.handle("drain"), WithPhase(1))
+	sig.Register("queue", rec.handle("queue"), WithPhase(2), WithHandleTimeout(time.Second))
+
+	assert.NoError(t, sig.Close(nil))
+	assert.Equal(t, []string{"listener", "drain", "drain", "queue", "db"}, rec.order())
+
+	reports := make(map[string]HandleReport)
+	for _, r := range sig.Report() {
+		reports[r.Name] = r
+	}
+	notBefore := func(later, earlier string) {
+		assert.False(t, reports[later].Start.Before(reports[earlier].End), "%s started before %s ended", later, earlier)
+	}
+	notBefore("drain-1", "listener")
+	notBefore("drain-2", "listener")
+	notBefore("queue", "drain-1")
+	notBefore("queue", "drain-2")
+	notBefore("db", "queue")
+	assert.True(t, reports["drain-2"].Start.Before(reports["drain-1"].End), "handles in the same phase should run concurrently")
+}
+
+func TestTerminateSignal_ConcurrentRegister(t *testing.T) {
+	sig := NewTerminateSignal()
+	assert.NotNil(t, sig, "signal is nil")
+	var executed, rejected int64
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := sig.RegisterCancelHandles(func() {
+				mu.Lock()
+				executed++
+				mu.Unlock()
+			})
+			if err != nil {
+				assert.True(t, errors.Is(err, ErrAlreadyClosed))
+				mu.Lock()
+				rejected++
+				mu.Unlock()
+			}
+		}()
+	}
+	assert.NoError(t, sig.Close(nil))
+	wg.Wait()
+	assert.Equal(t, int64(100), executed+rejected)
+	unregister, err := sig.Register("late", func(ctx context.Context) error { return nil })
+	assert.Equal(t, ErrAlreadyClosed, err)
+	assert.False(t, unregister())
+	assert.Equal(t, ErrAlreadyClosed, sig.RegisterShutdownHandles(func(ctx context.Context) error { return nil }))
+	assert.Equal(t, ErrAlreadyClosed, sig.RegisterCancelHandleWithTimeout(func() {}, time.Second))
+}
+
+func TestTerminateSignal_Unregister(t *testing.T) {
+	sig := NewTerminateSignal()
+	assert.NotNil(t, sig, "signal is nil")
+	rec := newTestRecorder()
+	unregisters := make([]func() bool, 0)
+	for i := 0; i < 5; i++ {
+		unregister, err := sig.Register(fmt.Sprintf("conn-%d", i), rec.handle(fmt.Sprintf("conn-%d", i)))
+		assert.NoError(t, err)
+		unregisters = append(unregisters, unregister)
+	}
+	assert.True(t, unregisters[1]())
+	assert.False(t, unregisters[1]())
+	assert.True(t, unregisters[3]())
+
+	assert.NoError(t, sig.SyncClose(nil))
+	assert.False(t, unregisters[0]())
+	assert.Equal(t, []string{"conn-0", "conn-2", "conn-4"}, rec.order())
+	assert.Len(t, sig.Report(), 3)
+}
+
+func TestTerminateSignal_StopContextAndDone(t *testing.T) {
+	sig := NewTerminateSignal()
+	assert.NotNil(t, sig, "signal is nil")
+	release := make(chan struct{})
+	sig.Register("drain", func(ctx context.Context) error {
+		assert.Error(t, sig.GetStopContext().Err(), "stop context should be cancelled before handles run")
+		<-release
+		return nil
+	})
+
+	go sig.Close(nil)
+
+	select {
+	case <-sig.GetStopContext().Done():
+	case <-time.After(time.Second):
+		assert.Fail(t, "stop context was not cancelled")
+	}
+
+	select {
+	case <-sig.Done():
+		assert.Fail(t, "done channel closed before handles completed")
+	default:
+	}
+
+	close(release)
+
+	select {
+	case <-sig.Done():
+	case <-time.After(time.Second):
+		assert.Fail(t, "done channel was not closed")
+	}
+}
+
+func TestTerminateSignal_Go(t *testing.T) {
+	sig := NewTerminateSignal()
+	assert.NotNil(t, sig, "signal is nil")
+	errWorker := errors.New("worker failed")
+
+	rec := newTestRecorder()
+	assert.NoError(t, sig.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(100 * time.Millisecond)
+		rec.add("worker")
+		return ctx.Err()
+	}))
+	assert.NoError(t, sig.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return errWorker
+	}))
+	sig.Register("db", rec.handle("db"))
+
+	err := sig.Close(nil)
+	assert.ErrorIs(t, err, errWorker)
+	assert.NotErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"worker", "db"}, rec.order())
+	assert.ErrorIs(t, sig.Go(func(ctx context.Context) error { return nil }), ErrAlreadyClosed)
+}